@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/tracker"
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+)
+
+type activityMsg struct {
+	version string
+	events  []ui.ActivityEvent
+	err     error
+}
+
+type commitsMsg struct {
+	version string
+	commits []ui.Commit
+	err     error
+}
+
+type diffMsg struct {
+	spec string
+	diff string
+	err  error
+}
+
+type tasksMsg struct {
+	version string
+	prKey   string
+	tasks   []ui.Task
+	err     error
+}
+
+// taskSavedMsg reports a task created or updated on a PR
+type taskSavedMsg struct {
+	version string
+	message string
+	err     error
+}
+
+type commentsMsg struct {
+	prKey    string
+	comments []api.Comment
+	err      error
+}
+
+type issueMsg struct {
+	key   string
+	issue *ui.Issue
+	err   error
+}
+
+// fetchActivityCmd loads the activity timeline of a PR
+func fetchActivityCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		activity, err := client.ListPRActivity(pr.Repo, pr.ID)
+		if err != nil {
+			return activityMsg{version: pr.Version(), err: err}
+		}
+
+		timeline := api.Timeline(activity)
+		events := make([]ui.ActivityEvent, len(timeline))
+		for i, e := range timeline {
+			events[i] = ui.ActivityEvent{Kind: ui.ActivityKind(e.Kind), Actor: e.Actor.FullName, At: e.Date, Summary: e.Summary}
+		}
+		return activityMsg{version: pr.Version(), events: events}
+	}
+}
+
+// fetchCommitsCmd loads the commits of a PR
+func fetchCommitsCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := client.ListPRCommits(pr.Repo, pr.ID)
+		if err != nil {
+			return commitsMsg{version: pr.Version(), err: err}
+		}
+
+		uiCommits := make([]ui.Commit, len(commits))
+		for i, c := range commits {
+			summary, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+			uiCommits[i] = ui.Commit{
+				Hash:     c.Hash[:min(7, len(c.Hash))],
+				FullHash: c.Hash,
+				Author:   c.Author.Name(),
+				Date:     c.Date,
+				Summary:  summary,
+			}
+		}
+		return commitsMsg{version: pr.Version(), commits: uiCommits}
+	}
+}
+
+// fetchDiffCmd loads a diff for the diff viewer
+func fetchDiffCmd(client *api.Client, repoSlug, spec string) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := client.FetchDiff(repoSlug, spec)
+		return diffMsg{spec: spec, diff: diff, err: err}
+	}
+}
+
+// fetchTasksCmd loads the tasks of a PR
+func fetchTasksCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := client.ListPRTasks(pr.Repo, pr.ID)
+		if err != nil {
+			return tasksMsg{version: pr.Version(), prKey: pr.Key(), err: err}
+		}
+
+		uiTasks := make([]ui.Task, len(tasks))
+		for i, t := range tasks {
+			uiTasks[i] = ui.Task{
+				ID:        t.ID,
+				Resolved:  t.Resolved(),
+				Content:   t.Content.Raw,
+				Creator:   t.Creator.FullName,
+				CreatedAt: t.CreatedOn,
+			}
+			if t.Comment != nil {
+				uiTasks[i].CommentID = t.Comment.ID
+			}
+		}
+		// Open tasks first, each group in creation order
+		slices.SortStableFunc(uiTasks, func(a, b ui.Task) int {
+			if a.Resolved == b.Resolved {
+				return 0
+			}
+			if b.Resolved {
+				return -1
+			}
+			return 1
+		})
+		return tasksMsg{version: pr.Version(), prKey: pr.Key(), tasks: uiTasks}
+	}
+}
+
+// createTaskCmd adds a task to a PR
+func createTaskCmd(client *api.Client, pr ui.PR, draft ui.TaskDraft) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.CreatePRTask(pr.Repo, pr.ID, draft.Content, draft.CommentID)
+		return taskSavedMsg{version: pr.Version(), message: "Task added", err: err}
+	}
+}
+
+// toggleTaskCmd resolves an open task or reopens a resolved one
+func toggleTaskCmd(client *api.Client, pr ui.PR, task ui.Task) tea.Cmd {
+	return func() tea.Msg {
+		state, message := api.TaskResolved, "Task resolved"
+		if task.Resolved {
+			state, message = api.TaskUnresolved, "Task reopened"
+		}
+		_, err := client.UpdatePRTask(pr.Repo, pr.ID, task.ID, "", state)
+		return taskSavedMsg{version: pr.Version(), message: message, err: err}
+	}
+}
+
+// fetchCommentsCmd loads the comments of a PR to pick one for a new task
+func fetchCommentsCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		comments, err := client.ListPRComments(pr.Repo, pr.ID)
+		return commentsMsg{prKey: pr.Key(), comments: comments, err: err}
+	}
+}
+
+// fetchIssueCmd fetches the summary and status of an issue from the tracker
+func fetchIssueCmd(client tracker.Client, key string) tea.Cmd {
+	return func() tea.Msg {
+		issue, err := client.FetchIssue(key)
+		if err != nil {
+			return issueMsg{key: key, err: err}
+		}
+
+		category := ui.IssueTodo
+		switch issue.Category {
+		case tracker.CategoryInProgress:
+			category = ui.IssueInProgress
+		case tracker.CategoryDone:
+			category = ui.IssueDone
+		}
+		return issueMsg{key: key, issue: &ui.Issue{Key: issue.Key, Summary: issue.Summary, Status: issue.Status, Category: category}}
+	}
+}
+
+// openCollapseMenu lists the timeline event types to fold or unfold
+func (m *model) openCollapseMenu() {
+	m.collapse.Open(m.collapseItems())
+}
+
+func (m *model) collapseItems() []ui.MenuItem {
+	counts := m.prDetail.ActivityCounts()
+	shortcuts := map[ui.ActivityKind]string{
+		ui.ActivityComment:        "c",
+		ui.ActivityApproval:       "a",
+		ui.ActivityChangesRequest: "x",
+		ui.ActivityUpdate:         "u",
+	}
+
+	items := make([]ui.MenuItem, len(ui.ActivityKinds))
+	for i, kind := range ui.ActivityKinds {
+		status := "shown"
+		if m.prDetail.IsCollapsed(kind) {
+			status = "collapsed"
+		}
+		items[i] = ui.MenuItem{Key: shortcuts[kind], Label: kind.Plural(), Value: fmt.Sprintf("%s (%d)", status, counts[kind])}
+	}
+	return items
+}
+
+// updateCollapseMenu toggles the event type picked with enter or its
+// shortcut, keeping the menu open to toggle others
+func (m *model) updateCollapseMenu(msg tea.KeyMsg) {
+	var item *ui.MenuItem
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.collapse.Close()
+		return
+	case key.Matches(msg, m.keys.Up):
+		m.collapse.MoveUp()
+		return
+	case key.Matches(msg, m.keys.Down):
+		m.collapse.MoveDown()
+		return
+	case key.Matches(msg, m.keys.Enter):
+		item = m.collapse.GetSelected()
+	default:
+		item = m.collapse.ItemForKey(msg.String())
+	}
+
+	if item == nil {
+		return
+	}
+	for i, kind := range ui.ActivityKinds {
+		if kind.Plural() == item.Label {
+			m.prDetail.ToggleCollapsed(kind)
+			m.collapse.Cursor = i
+		}
+	}
+	m.collapse.Items = m.collapseItems()
+}
+
+// detailCmd records the first visit to the shown PR this session and fetches
+// the data its tab needs
+func (m *model) detailCmd() tea.Cmd {
+	pr := m.prDetail.PR
+	if pr == nil {
+		return nil
+	}
+
+	if !m.prDetail.HasLastSeen(pr.Key()) {
+		seen, _ := m.state.LastSeen(pr.Key())
+		m.prDetail.SetLastSeen(pr.Key(), seen)
+		m.state.MarkSeen(pr.Key(), time.Now())
+	}
+
+	var cmds []tea.Cmd
+	if m.tracker != nil {
+		for _, key := range m.prDetail.PendingIssues() {
+			cmds = append(cmds, fetchIssueCmd(m.tracker, key))
+		}
+	}
+
+	tab, ok := m.prDetail.Pending()
+	if !ok {
+		return tea.Batch(cmds...)
+	}
+	switch tab {
+	case ui.TabActivity:
+		cmds = append(cmds, fetchActivityCmd(m.client, *pr))
+	case ui.TabCommits:
+		cmds = append(cmds, fetchCommitsCmd(m.client, *pr))
+	case ui.TabTasks:
+		cmds = append(cmds, fetchTasksCmd(m.client, *pr))
+	case ui.TabChecks:
+		if pr.State == "OPEN" {
+			cmds = append(cmds, fetchChecksCmd(m.client, *pr))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// openCommitDiff shows the diff of the commit selected on the commits tab
+func (m *model) openCommitDiff() tea.Cmd {
+	commit := m.prDetail.SelectedCommit()
+	if commit == nil {
+		return nil
+	}
+
+	m.diff.Open(commit.Hash+" "+commit.Summary, commit.FullHash)
+	return fetchDiffCmd(m.client, m.prDetail.PR.Repo, commit.FullHash)
+}
+
+// commentItems lists the comments a task can be attached to
+func commentItems(comments []api.Comment) []ui.MenuItem {
+	var items []ui.MenuItem
+	for _, c := range comments {
+		if c.Deleted {
+			continue
+		}
+		label := c.User.FullName
+		if c.Inline != nil {
+			label += " on " + c.Inline.Path
+		}
+		items = append(items, ui.MenuItem{Label: label, Value: c.Content.Raw, ID: c.ID})
+	}
+	return items
+}
+
+// updateCommentPicker handles key presses while picking the comment for a
+// new task, which then opens the task prompt prefilled with the comment
+func (m *model) updateCommentPicker(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.comments.Close()
+	case key.Matches(msg, m.keys.Up):
+		m.comments.MoveUp()
+	case key.Matches(msg, m.keys.Down):
+		m.comments.MoveDown()
+	case key.Matches(msg, m.keys.Enter):
+		item := m.comments.GetSelected()
+		if item == nil {
+			return nil
+		}
+		m.comments.Close()
+		text, _, _ := strings.Cut(strings.TrimSpace(item.Value), "\n")
+		return m.prDetail.StartTask(text, item.ID)
+	}
+	return nil
+}
+
+// updateDiffView handles key presses while the diff viewer is open
+func (m *model) updateDiffView(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.diff.Close()
+	case key.Matches(msg, m.keys.Up):
+		m.diff.ScrollUp()
+	case key.Matches(msg, m.keys.Down):
+		m.diff.ScrollDown()
+	case key.Matches(msg, m.keys.HalfPageUp):
+		m.diff.ScrollUpHalf()
+	case key.Matches(msg, m.keys.HalfPageDown):
+		m.diff.ScrollDownHalf()
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/config"
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+	"github.com/anasalqoyyum/lazy-bb/internal/utils"
+)
+
+type editDataMsg struct {
+	prKey string
+	data  ui.EditData
+	err   error
+}
+
+type prSavedMsg struct {
+	id      int
+	created bool
+	err     error
+}
+
+// branchCommitsMsg carries the commit summaries of a new PR's branches, oldest first
+type branchCommitsMsg struct {
+	source      string
+	destination string
+	commits     []string
+}
+
+// draftPurpose is what text written in the external editor is for
+type draftPurpose int
+
+const (
+	draftDescription draftPurpose = iota
+	draftComment
+	draftDecline
+)
+
+// draftMsg carries a draft ready to be opened in the editor
+type draftMsg struct {
+	purpose draftPurpose
+	pr      ui.PR
+	draft   *utils.Draft
+	err     error
+}
+
+// editorMsg carries the text written in the editor
+type editorMsg struct {
+	purpose draftPurpose
+	pr      ui.PR
+	text    string
+	err     error
+}
+
+// prActionMsg reports a change made to a PR, such as a comment
+type prActionMsg struct {
+	message string
+	err     error
+	// reload fetches the PRs again, otherwise only the activity tab is
+	reload  bool
+	version string
+}
+
+// fetchEditDataCmd loads the current values of a PR for the edit form, with
+// the people and branches to suggest. Suggestions are optional, so failing
+// to load them leaves autocomplete empty
+func fetchEditDataCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		current, err := client.FetchPR(pr.Repo, pr.ID)
+		if err != nil {
+			return editDataMsg{prKey: pr.Key(), err: err}
+		}
+
+		data := ui.EditData{
+			Title:       current.Title,
+			Description: current.Description,
+			Destination: current.Destination.Branch.Name,
+			UpdatedAt:   current.UpdatedOn,
+			AuthorUUID:  current.Author.UUID,
+		}
+		for _, r := range current.Reviewers {
+			data.Reviewers = append(data.Reviewers, ui.Person{Name: r.FullName, Nickname: r.Nickname, UUID: r.UUID})
+		}
+
+		if members, err := client.ListWorkspaceMembers(); err == nil {
+			for _, u := range members {
+				data.People = append(data.People, ui.Person{Name: u.FullName, Nickname: u.Nickname, UUID: u.UUID})
+			}
+		}
+		if branches, err := client.ListBranches(pr.Repo); err == nil {
+			for _, b := range branches {
+				data.Branches = append(data.Branches, b.Name)
+			}
+		}
+
+		return editDataMsg{prKey: pr.Key(), data: data}
+	}
+}
+
+// fetchCreateDataCmd loads what the form of a new PR suggests: branches,
+// people and description templates, from the repository at its main branch
+// first then from the config file. Only the branches are required
+func fetchCreateDataCmd(client *api.Client, target ui.PR, templates []config.TemplateConfig, author string) tea.Cmd {
+	return func() tea.Msg {
+		branches, err := client.ListBranches(target.Repo)
+		if err != nil {
+			return editDataMsg{prKey: target.Key(), err: err}
+		}
+
+		data := ui.EditData{AuthorUUID: author}
+		for _, b := range branches {
+			data.Branches = append(data.Branches, b.Name)
+		}
+
+		if mainBranch, err := client.FetchMainBranch(target.Repo); err == nil {
+			data.Destination = mainBranch
+			if repoTemplates, err := client.ListPRTemplates(target.Repo, mainBranch); err == nil {
+				for _, t := range repoTemplates {
+					data.Templates = append(data.Templates, ui.Template{Name: t.Name, Body: t.Body})
+				}
+			}
+		}
+		for _, t := range templates {
+			if matchesAny(t.Repos, target.Repo) {
+				data.Templates = append(data.Templates, ui.Template{Name: t.Name, Body: t.Body})
+			}
+		}
+
+		if members, err := client.ListWorkspaceMembers(); err == nil {
+			for _, u := range members {
+				data.People = append(data.People, ui.Person{Name: u.FullName, Nickname: u.Nickname, UUID: u.UUID})
+			}
+		}
+
+		return editDataMsg{prKey: target.Key(), data: data}
+	}
+}
+
+// matchesAny reports whether a repository slug matches one of the globs, or there are none
+func matchesAny(globs []string, slug string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	for _, g := range globs {
+		if matched, err := path.Match(g, slug); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// maxTemplateCommits bounds the commits listed in a description template
+const maxTemplateCommits = 50
+
+// fetchBranchCommitsCmd loads the summaries of the commits a new PR would
+// merge. They only fill in the template, so failing leaves them out
+func fetchBranchCommitsCmd(client *api.Client, repo string, branches ui.BranchesChangedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg := branchCommitsMsg{source: branches.Source, destination: branches.Destination}
+		commits, err := client.ListBranchCommits(repo, branches.Source, branches.Destination, maxTemplateCommits)
+		if err != nil {
+			return msg
+		}
+
+		for _, c := range slices.Backward(commits) {
+			summary, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+			msg.commits = append(msg.commits, summary)
+		}
+		return msg
+	}
+}
+
+// createPRCmd opens a PR from the create form
+func createPRCmd(client *api.Client, repo string, draft ui.PRDraft) tea.Cmd {
+	return func() tea.Msg {
+		create := api.PRCreate{
+			Title:             draft.Title,
+			Description:       draft.Description,
+			Source:            draft.Source,
+			Destination:       draft.Destination,
+			CloseSourceBranch: draft.CloseSourceBranch,
+		}
+		for _, r := range draft.Reviewers {
+			create.Reviewers = append(create.Reviewers, r.UUID)
+		}
+		pr, err := client.CreatePR(repo, create)
+		if err != nil {
+			return prSavedMsg{created: true, err: err}
+		}
+		return prSavedMsg{id: pr.ID, created: true}
+	}
+}
+
+// updatePRCmd saves the edit form, refusing when the PR changed meanwhile
+func updatePRCmd(client *api.Client, pr ui.PR, draft ui.PRDraft) tea.Cmd {
+	return func() tea.Msg {
+		update := api.PRUpdate{
+			Title:       draft.Title,
+			Description: draft.Description,
+			Destination: draft.Destination,
+		}
+		for _, r := range draft.Reviewers {
+			update.Reviewers = append(update.Reviewers, r.UUID)
+		}
+		_, err := client.UpdatePR(pr.Repo, pr.ID, update, draft.UpdatedAt)
+		return prSavedMsg{id: pr.ID, err: err}
+	}
+}
+
+// prepareDraftCmd writes the text to edit to a temporary file, with the PR
+// and its changed files as context
+func prepareDraftCmd(client *api.Client, pr ui.PR, purpose draftPurpose, text string) tea.Cmd {
+	return func() tea.Msg {
+		var context strings.Builder
+		switch purpose {
+		case draftDescription:
+			context.WriteString("Description of ")
+		case draftComment:
+			context.WriteString("Comment on ")
+		case draftDecline:
+			context.WriteString("Reason for declining ")
+		}
+		if pr.ID == 0 {
+			fmt.Fprintf(&context, "a new PR: %s\n%s → %s\n", pr.Title, pr.SourceBranch, pr.DestBranch)
+		} else {
+			fmt.Fprintf(&context, "PR #%d: %s\n%s → %s\n", pr.ID, pr.Title, pr.SourceBranch, pr.DestBranch)
+		}
+
+		// The context is a courtesy, the draft is still opened without it
+		// A new PR has no changes to list yet
+		if pr.ID != 0 {
+			if stats, err := client.ListPRDiffStat(pr.Repo, pr.ID); err == nil && len(stats) > 0 {
+				context.WriteString("\nChanges:\n")
+				for _, s := range stats {
+					fmt.Fprintf(&context, "  %s %s  +%d -%d\n", diffStatLetter(s), s.Path(), s.LinesAdded, s.LinesRemoved)
+				}
+			}
+		}
+
+		draft, err := utils.NewDraft(text, context.String(), ".md")
+		return draftMsg{purpose: purpose, pr: pr, draft: draft, err: err}
+	}
+}
+
+// diffStatLetter abbreviates the change of a file the way git status does
+func diffStatLetter(s api.DiffStat) string {
+	switch {
+	case s.Conflicted():
+		return "C"
+	case s.Status == "added":
+		return "A"
+	case s.Status == "removed":
+		return "D"
+	case s.Status == "renamed":
+		return "R"
+	default:
+		return "M"
+	}
+}
+
+// openEditorCmd suspends the TUI while the draft is edited
+func openEditorCmd(msg draftMsg) tea.Cmd {
+	return tea.ExecProcess(msg.draft.Command(), func(err error) tea.Msg {
+		if err != nil {
+			os.Remove(msg.draft.Path)
+			return editorMsg{purpose: msg.purpose, pr: msg.pr, err: fmt.Errorf("failed to run editor: %w", err)}
+		}
+		text, err := msg.draft.Read()
+		return editorMsg{purpose: msg.purpose, pr: msg.pr, text: text, err: err}
+	})
+}
+
+// commentCmd adds a comment to a PR
+func commentCmd(client *api.Client, pr ui.PR, text string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.CreatePRComment(pr.Repo, pr.ID, text)
+		return prActionMsg{message: "Comment added", err: err, version: pr.Version()}
+	}
+}
+
+// declineCmd declines a PR, leaving the reason as a comment
+func declineCmd(client *api.Client, pr ui.PR, reason string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.DeclinePR(pr.Repo, pr.ID, reason)
+		return prActionMsg{message: fmt.Sprintf("Declined #%d", pr.ID), err: err, reload: true}
+	}
+}
+
+// openEditForm edits the selected PR once its current values are loaded
+func (m *model) openEditForm() tea.Cmd {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return nil
+	}
+	if selected.State != "OPEN" {
+		return m.flash("Only open PRs can be edited")
+	}
+
+	m.edit.Open(*selected)
+	return fetchEditDataCmd(m.client, *selected)
+}
+
+// openCreateForm fills in a new PR in the selected repository
+func (m *model) openCreateForm() tea.Cmd {
+	if m.selectedRepo == nil {
+		return m.flash("Select a repository to create a PR in")
+	}
+
+	author := ""
+	if m.user != nil {
+		author = m.user.UUID
+	}
+
+	m.edit.OpenCreate(m.selectedRepo.Slug)
+	return fetchCreateDataCmd(m.client, m.edit.Target(), m.templates, author)
+}
+
+// writeDraft opens the external editor to write a comment on the selected
+// PR or the reason for declining it
+func (m *model) writeDraft(purpose draftPurpose) tea.Cmd {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return nil
+	}
+	if purpose == draftDecline && selected.State != "OPEN" {
+		return m.flash("Only open PRs can be declined")
+	}
+	return prepareDraftCmd(m.client, *selected, purpose, "")
+}
+
+// useDraft acts on the text written in the external editor. An empty text
+// aborts whatever it was written for
+func (m *model) useDraft(msg editorMsg) tea.Cmd {
+	if errors.Is(msg.err, utils.ErrEmptyDraft) {
+		switch msg.purpose {
+		case draftDescription:
+			m.edit.SetNotice("Nothing was written, the description is unchanged")
+			return nil
+		case draftDecline:
+			return m.flash("Decline aborted: no reason was written")
+		default:
+			return m.flash("Comment aborted: nothing was written")
+		}
+	}
+	if msg.err != nil {
+		if msg.purpose == draftDescription {
+			m.edit.SetError(msg.err)
+			return nil
+		}
+		return m.flash(msg.err.Error())
+	}
+
+	switch msg.purpose {
+	case draftDescription:
+		if m.edit.Visible && m.edit.Target().Key() == msg.pr.Key() {
+			m.edit.SetDescription(msg.text)
+		}
+		return nil
+	case draftDecline:
+		return declineCmd(m.client, msg.pr, msg.text)
+	default:
+		return commentCmd(m.client, msg.pr, msg.text)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+	"github.com/anasalqoyyum/lazy-bb/internal/utils"
+)
+
+// copiedMsg reports whether text was put on the clipboard
+type copiedMsg struct {
+	what string
+	text string
+	err  error
+}
+
+type linksMsg struct {
+	prKey string
+	links []ui.Link
+	// err is the first failure; the links gathered without it are still listed
+	err error
+}
+
+// fetchLinksCmd gathers every link of a PR: the PR itself, URLs and Jira keys
+// in its description and comments, and build status pages. A failed request
+// only leaves out the links it would have found
+func fetchLinksCmd(client *api.Client, pr ui.PR, jiraURL string) tea.Cmd {
+	return func() tea.Msg {
+		links := []ui.Link{{Source: "pr", Label: fmt.Sprintf("#%d %s", pr.ID, pr.Title), URL: pr.Links.HTML.Href}}
+		links = append(links, ui.JiraLinks("jira", pr.Title+" "+pr.SourceBranch, jiraURL)...)
+		links = append(links, ui.ExtractLinks("description", pr.Description)...)
+		links = append(links, ui.JiraLinks("description", pr.Description, jiraURL)...)
+
+		comments, commentsErr := client.ListPRComments(pr.Repo, pr.ID)
+		for _, comment := range comments {
+			if comment.Deleted {
+				continue
+			}
+			source := "comment by " + comment.User.FullName
+			links = append(links, ui.ExtractLinks(source, comment.Content.Raw)...)
+			links = append(links, ui.JiraLinks(source, comment.Content.Raw, jiraURL)...)
+		}
+
+		statuses, statusesErr := client.FetchPRStatuses(pr.Repo, pr.ID)
+		for _, status := range statuses {
+			if status.URL != "" {
+				links = append(links, ui.Link{Source: "build", Label: status.Name + " (" + strings.ToLower(status.State) + ")", URL: status.URL})
+			}
+		}
+
+		return linksMsg{prKey: pr.Key(), links: ui.UniqueLinks(links), err: cmp.Or(commentsErr, statusesErr)}
+	}
+}
+
+// openURL opens a URL in the browser, showing it to copy when that fails
+func (m *model) openURL(url string) {
+	if err := m.opener.Open(url); err != nil {
+		m.urlModal.Open(url, err)
+	}
+}
+
+// updateURLModal handles key presses while an unopened URL is shown
+func (m *model) updateURLModal(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.urlModal.Close()
+	case key.Matches(msg, m.keys.CopyLink):
+		m.urlModal.Close()
+		return copyCmd("URL", m.urlModal.URL)
+	}
+	return nil
+}
+
+// openLinkPicker lists the links of the selected PR
+func (m *model) openLinkPicker() tea.Cmd {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return nil
+	}
+
+	m.links.Open(selected.Key())
+	return fetchLinksCmd(m.client, *selected, m.jiraURL)
+}
+
+// updateLinkPicker handles key presses while the link picker is open
+func (m *model) updateLinkPicker(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.links.Close()
+	case key.Matches(msg, m.keys.Up):
+		m.links.MoveUp()
+	case key.Matches(msg, m.keys.Down):
+		m.links.MoveDown()
+	case key.Matches(msg, m.keys.Enter):
+		if link := m.links.GetSelected(); link != nil {
+			m.links.Close()
+			m.openURL(link.URL)
+		}
+	case key.Matches(msg, m.keys.CopyLink):
+		if link := m.links.GetSelected(); link != nil {
+			m.links.Close()
+			return copyCmd("link", link.URL)
+		}
+	}
+	return nil
+}
+
+// openYankMenu offers the values of the selected PR to copy
+func (m *model) openYankMenu() {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return
+	}
+
+	url := selected.Links.HTML.Href
+	branch := selected.SourceBranch
+	m.yank.Open([]ui.MenuItem{
+		{Key: "u", Label: "URL", Value: url},
+		{Key: "i", Label: "ID", Value: fmt.Sprintf("#%d", selected.ID)},
+		{Key: "t", Label: "title", Value: selected.Title},
+		{Key: "b", Label: "branch", Value: branch},
+		{Key: "m", Label: "markdown link", Value: fmt.Sprintf("[#%d %s](%s)", selected.ID, selected.Title, url)},
+		{Key: "g", Label: "checkout command", Value: checkoutCommand(selected)},
+	})
+}
+
+// checkoutCommand is the shell command checking out the source branch of a PR.
+// Branches of forks are fetched from the fork's clone URL into a local branch
+func checkoutCommand(pr *ui.PR) string {
+	branch := shellQuote(pr.SourceBranch)
+	if pr.SourceRepo == "" || pr.SourceRepo == pr.Workspace+"/"+pr.Repo {
+		return fmt.Sprintf("git fetch origin %s && git checkout %s", branch, branch)
+	}
+
+	cloneURL := "https://bitbucket.org/" + pr.SourceRepo + ".git"
+	if link, err := url.Parse(pr.Links.HTML.Href); err == nil && link.Host != "" {
+		cloneURL = fmt.Sprintf("%s://%s/%s.git", link.Scheme, link.Host, pr.SourceRepo)
+	}
+	refspec := shellQuote(pr.SourceBranch + ":" + pr.SourceBranch)
+	return fmt.Sprintf("git fetch %s %s && git checkout %s", shellQuote(cloneURL), refspec, branch)
+}
+
+// shellQuote quotes s as a single word for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// updateYankMenu handles key presses while the yank menu is open, copying
+// the item picked with enter or its shortcut
+func (m *model) updateYankMenu(msg tea.KeyMsg) tea.Cmd {
+	var item *ui.MenuItem
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		m.yank.Close()
+		return nil
+	case key.Matches(msg, m.keys.Up):
+		m.yank.MoveUp()
+		return nil
+	case key.Matches(msg, m.keys.Down):
+		m.yank.MoveDown()
+		return nil
+	case key.Matches(msg, m.keys.Enter):
+		item = m.yank.GetSelected()
+	default:
+		item = m.yank.ItemForKey(msg.String())
+	}
+
+	if item == nil {
+		return nil
+	}
+	m.yank.Close()
+	return copyCmd(item.Label, item.Value)
+}
+
+// copyCmd puts text on the clipboard in the background, as clipboard tools
+// may be slow to return
+func copyCmd(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return copiedMsg{what: what, text: text, err: utils.Copy(text)}
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,7 +15,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/cli"
 	"github.com/anasalqoyyum/lazy-bb/internal/config"
+	"github.com/anasalqoyyum/lazy-bb/internal/state"
+	"github.com/anasalqoyyum/lazy-bb/internal/tracker"
 	"github.com/anasalqoyyum/lazy-bb/internal/ui"
 	"github.com/anasalqoyyum/lazy-bb/internal/utils"
 )
@@ -21,6 +27,16 @@ type errMsg error
 
 type reposMsg struct {
 	repos []ui.Repository
+	role  string
+}
+
+type openPRCountsMsg struct {
+	counts map[string]int
+	role   string
+}
+
+type userMsg struct {
+	user *api.AuthorInfo
 }
 
 type statusMsg struct {
@@ -28,6 +44,14 @@ type statusMsg struct {
 	repoSlug string
 }
 
+// clearFlashMsg hides the status bar message it was scheduled for
+type clearFlashMsg struct {
+	id int
+}
+
+// flashDuration is how long status bar messages stay visible
+const flashDuration = 3 * time.Second
+
 type model struct {
 	spinner           spinner.Model
 	quitting          bool
@@ -44,97 +68,125 @@ type model struct {
 	selectedRepo      *ui.Repository
 	loadingPRs        bool
 	lastRequestedRepo string
+	keys              *ui.KeyMap
+	help              *ui.HelpView
+	state             *state.State
+	lastClick         click
+	user              *api.AuthorInfo
+	statusBar         *ui.StatusBar
+	repoRole          string
+	// restore is the session being restored until the first PRs have loaded
+	restore *state.Session
+	layout  ui.Layout
+	// listPane is the list shown in the stacked layout, the last one focused
+	listPane ui.Pane
+	links    *ui.LinkPicker
+	jiraURL  string
+	// tracker fetches the issues of the shown PR, nil unless enabled in the config file
+	tracker  tracker.Client
+	yank     *ui.Menu
+	collapse *ui.Menu
+	diff     *ui.DiffView
+	// comments picks the comment a new task is attached to
+	comments *ui.Menu
+	opener   utils.Opener
+	// urlModal shows URLs the opener failed on
+	urlModal *ui.URLModal
+	merge    *ui.MergeDialog
+	edit     *ui.EditForm
+	// templates are the description templates of the config file
+	templates []config.TemplateConfig
+	// views are the saved views by source ID
+	views map[string]savedView
+	// fanOut is the dashboard or view being fetched, nil when none is
+	fanOut   *fanOut
+	fanOutID int
+	// sourceCtx lasts as long as the current source is shown: the requests
+	// made for it are cancelled when another source is loaded
+	sourceCtx    context.Context
+	cancelSource context.CancelFunc
+	// mergeOptions are sent with every merge, from the config file
+	mergeOptions api.MergeOptions
+	// flashID identifies the latest status bar message so older timers leave it alone
+	flashID int
 }
 
-var quitKeys = key.NewBinding(
-	key.WithKeys("q", "esc", "ctrl+c"),
-	key.WithHelp("q/esc", "q to quit"),
-)
-
-var upKeys = key.NewBinding(
-	key.WithKeys("up", "k"),
-	key.WithHelp("↑/k", "up"),
-)
-
-var downKeys = key.NewBinding(
-	key.WithKeys("down", "j"),
-	key.WithHelp("↓/j", "down"),
-)
-
-var enterKeys = key.NewBinding(
-	key.WithKeys("enter"),
-	key.WithHelp("enter", "open in browser"),
-)
-
-var focusPRListKeys = key.NewBinding(
-	key.WithKeys("1"),
-	key.WithHelp("1", "focus PR list"),
-)
-
-var focusDetailKeys = key.NewBinding(
-	key.WithKeys("2"),
-	key.WithHelp("2", "focus detail"),
-)
-
-var focusRepoListKeys = key.NewBinding(
-	key.WithKeys("3"),
-	key.WithHelp("3", "focus repo list"),
-)
-
-var cycleLeftPaneKeys = key.NewBinding(
-	key.WithKeys("tab"),
-	key.WithHelp("tab", "cycle PR/Repo"),
-)
-
-var refreshKeys = key.NewBinding(
-	key.WithKeys("r"),
-	key.WithHelp("r", "refresh PR list"),
-)
-
-var halfScrollUpKeys = key.NewBinding(
-	key.WithKeys("ctrl+u"),
-	key.WithHelp("ctrl+u", "half page up"),
-)
-
-var halfScrollDownKeys = key.NewBinding(
-	key.WithKeys("ctrl+d"),
-	key.WithHelp("ctrl+d", "half page down"),
-)
-
-func initialModel() model {
+func initialModel(keys *ui.KeyMap, columns []ui.Column, st *state.State, repoRole string) model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7aa2f7"))
+	s.Style = lipgloss.NewStyle().Foreground(ui.ActiveTheme().Accent)
 
 	// Use default sizes, will be updated on first WindowSizeMsg
 	halfWidth := 90
 	quarterHeight := 15
 
+	views := make([]ui.SidebarView, len(api.Dashboards))
+	for i, d := range api.Dashboards {
+		views[i] = ui.SidebarView{ID: dashboardPrefix + string(d), Name: d.Title()}
+	}
+
+	repoList := ui.NewRepoList(halfWidth, quarterHeight)
+	repoList.Views = views
+	repoList.Role = repoRole
+
 	return model{
-		spinner:  s,
-		loading:  true,
-		prList:   ui.NewPRList(halfWidth, quarterHeight),
-		prDetail: ui.NewPRDetail(halfWidth, quarterHeight*2),
-		repoList: ui.NewRepoList(halfWidth, quarterHeight),
-		width:    halfWidth * 2,
-		height:   quarterHeight * 4,
+		spinner:   s,
+		loading:   true,
+		prList:    ui.NewPRList(halfWidth, quarterHeight, columns),
+		prDetail:  ui.NewPRDetail(halfWidth, quarterHeight*2),
+		repoList:  repoList,
+		width:     halfWidth * 2,
+		height:    quarterHeight * 4,
+		keys:      keys,
+		help:      ui.NewHelpView(keys),
+		statusBar: &ui.StatusBar{},
+		state:     st,
+		repoRole:  repoRole,
+		layout:    ui.NewLayout(),
+		listPane:  ui.PanePRList,
+		links:     ui.NewLinkPicker(),
+		yank:      ui.NewMenu("Yank"),
+		collapse:  ui.NewMenu("Collapse events"),
+		diff:      ui.NewDiffView(),
+		comments:  ui.NewMenu("Task from comment"),
+		urlModal:  ui.NewURLModal(),
+		merge:     ui.NewMergeDialog(),
+		edit:      ui.NewEditForm(),
 	}
 }
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		fetchReposCmd(m.client),
+		fetchUserCmd(m.client),
+		fetchReposCmd(m.client, m.repoRole),
 	)
 }
 
-func fetchReposCmd(client *api.Client) tea.Cmd {
+// fetchUserCmd identifies the logged-in user. Failing to do so is not fatal,
+// PRs are then shown without the authored/review markers
+func fetchUserCmd(client *api.Client) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil {
+			return userMsg{}
+		}
+
+		user, err := client.CurrentUser()
+		if err != nil {
+			return userMsg{}
+		}
+
+		return userMsg{user: user}
+	}
+}
+
+func fetchReposCmd(client *api.Client, role string) tea.Cmd {
 	return func() tea.Msg {
 		if client == nil {
 			return errMsg(fmt.Errorf("client not initialized"))
 		}
 
-		repos, err := client.FetchRepositories("admin")
+		repos, err := client.ListRepositories(api.RepoListOptions{Role: role})
 		if err != nil {
 			return errMsg(err)
 		}
@@ -142,13 +194,39 @@ func fetchReposCmd(client *api.Client) tea.Cmd {
 		uiRepos := make([]ui.Repository, len(repos))
 		for i, repo := range repos {
 			uiRepos[i] = ui.Repository{
-				Slug:  repo.Slug,
-				Name:  repo.Name,
-				Links: ui.Links{HTML: ui.HTML{Href: repo.Links.HTML.Href}},
+				Slug:       repo.Slug,
+				Name:       repo.Name,
+				Links:      ui.Links{HTML: ui.HTML{Href: repo.Links.HTML.Href}},
+				ProjectKey: repo.Project.Key,
+				Project:    repo.Project.Name,
+				Language:   repo.Language,
+				Private:    repo.IsPrivate,
+				UpdatedAt:  repo.UpdatedOn,
+				OpenPRs:    -1,
+			}
+		}
+
+		return reposMsg{repos: uiRepos, role: role}
+	}
+}
+
+// fetchOpenPRCountsCmd counts the open PRs of each repository. A repository
+// that cannot be counted keeps showing an unknown count
+func fetchOpenPRCountsCmd(client *api.Client, role string, repos []ui.Repository) tea.Cmd {
+	return func() tea.Msg {
+		slugs := make([]string, len(repos))
+		for i, repo := range repos {
+			slugs[i] = repo.Slug
+		}
+
+		counts := make(map[string]int, len(repos))
+		for result := range api.FetchEach(context.Background(), client, slugs, api.DefaultWorkers, (*api.Client).CountOpenPRs) {
+			if result.Err == nil {
+				counts[result.Item] = result.Value
 			}
 		}
 
-		return reposMsg{repos: uiRepos}
+		return openPRCountsMsg{counts: counts, role: role}
 	}
 }
 
@@ -167,170 +245,563 @@ func fetchPRsCmd(client *api.Client, repoSlug string) tea.Cmd {
 	}
 }
 
+// Update handles the message, then loads whatever the detail pane now needs
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	updated := next.(model)
+	return updated, tea.Batch(cmd, updated.detailCmd())
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Split width 50:50 between lists and detail, accounting for borders properly
-		// Each panel has 2-char borders (left and right), so effective content width is Width - 4 per panel
-		// We want: left_content + left_borders + right_content + right_borders = total_width
-		// Simplified: (width - 4) / 2 for each panel's inner content
-		panelWidth := (msg.Width - 4) / 2
-		quarterHeight := (msg.Height - 4) / 2 // -4 for borders, split in half
-
-		m.prList.Width = panelWidth
-		m.repoList.Width = panelWidth
-		m.prList.Height = quarterHeight
-		m.repoList.Height = quarterHeight
-
-		m.prDetail.Width = panelWidth
-		m.prDetail.Height = msg.Height - 2 // Full height minus border
+		m.help.Width = msg.Width
+		m.statusBar.Width = msg.Width
+		m.links.Width = msg.Width
+		m.links.Height = msg.Height
+		m.yank.Width = msg.Width
+		m.yank.Height = msg.Height
+		m.collapse.Width = msg.Width
+		m.collapse.Height = msg.Height
+		m.diff.Resize(msg.Width, msg.Height)
+		m.comments.Width = msg.Width
+		m.comments.Height = msg.Height
+		m.urlModal.Width = msg.Width
+		m.urlModal.Height = msg.Height
+		m.edit.Resize(msg.Width, msg.Height)
+		m.merge.Width = msg.Width
+		m.merge.Height = msg.Height
+		m.help.Height = msg.Height
+		m.resize()
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
-		if key.Matches(msg, quitKeys) {
+		if m.help.Visible {
+			return m, m.help.Update(msg)
+		}
+
+		if m.prDetail.Searching {
+			return m, m.prDetail.UpdateSearch(msg)
+		}
+
+		if m.prDetail.Composing {
+			cmd, draft := m.prDetail.UpdateTaskInput(msg)
+			if draft != nil && m.prDetail.PR != nil {
+				return m, createTaskCmd(m.client, *m.prDetail.PR, *draft)
+			}
+			return m, cmd
+		}
+
+		if m.edit.Visible {
+			cmd, draft := m.edit.Update(msg)
+			if draft != nil {
+				if m.edit.Creating() {
+					return m, createPRCmd(m.client, m.edit.Repo, *draft)
+				}
+				return m, updatePRCmd(m.client, *m.edit.PR, *draft)
+			}
+			return m, cmd
+		}
+
+		if m.links.Visible {
+			return m, m.updateLinkPicker(msg)
+		}
+
+		if m.yank.Visible {
+			return m, m.updateYankMenu(msg)
+		}
+
+		if m.urlModal.Visible {
+			return m, m.updateURLModal(msg)
+		}
+
+		if m.collapse.Visible {
+			m.updateCollapseMenu(msg)
+			return m, nil
+		}
+
+		if m.diff.Visible {
+			m.updateDiffView(msg)
+			return m, nil
+		}
+
+		if m.comments.Visible {
+			return m, m.updateCommentPicker(msg)
+		}
+
+		if m.merge.Visible {
+			return m, m.updateMergeDialog(msg)
+		}
+
+		if key.Matches(msg, m.keys.Quit) {
 			m.quitting = true
+			m.saveSession()
 			return m, tea.Quit
 		}
 
-		if key.Matches(msg, refreshKeys) && !m.loadingPRs {
+		if key.Matches(msg, m.keys.Help) {
+			m.help.Open(m.focusedPane())
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.Refresh) && !m.loadingPRs {
 			m.loadingPRs = true
-			return m, fetchPRsCmd(m.client, "")
+			m.prDetail.Invalidate()
+			cmd := m.fetchSource(m.lastRequestedRepo)
+			return m, cmd
 		}
 
-		if key.Matches(msg, focusPRListKeys) && !m.loadingPRs {
-			if len(m.prs) > 0 {
-				m.prList.Focused = true
-				m.prDetail.Focused = false
-				m.repoList.Focused = false
-			}
+		if key.Matches(msg, m.keys.FocusPRList) && !m.loadingPRs {
+			m.focus(ui.PanePRList)
 			return m, nil
 		}
 
-		if key.Matches(msg, focusDetailKeys) && !m.loadingPRs {
-			m.prList.Focused = false
-			m.prDetail.Focused = true
-			m.repoList.Focused = false
+		if key.Matches(msg, m.keys.FocusDetail) && !m.loadingPRs {
+			m.focus(ui.PaneDetail)
 			return m, nil
 		}
 
-		if key.Matches(msg, focusRepoListKeys) {
-			if len(m.repos) > 0 {
-				m.prList.Focused = false
-				m.prDetail.Focused = false
-				m.repoList.Focused = true
+		if key.Matches(msg, m.keys.FocusRepoList) {
+			m.focus(ui.PaneRepoList)
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.CycleLayout) {
+			m.layout.CycleMode()
+			m.resize()
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.ToggleZoom) {
+			m.layout.Zoom = !m.layout.Zoom
+			if m.layout.Zoom {
+				m.focus(ui.PaneDetail)
 			}
+			m.resize()
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.GrowSplit) {
+			m.layout.Grow()
+			m.resize()
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.ShrinkSplit) {
+			m.layout.Shrink()
+			m.resize()
 			return m, nil
 		}
 
-		if key.Matches(msg, cycleLeftPaneKeys) {
+		if key.Matches(msg, m.keys.CycleLeftPane) {
 			if m.prList.Focused {
-				if len(m.repos) > 0 {
-					m.prList.Focused = false
-					m.repoList.Focused = true
-				}
+				m.focus(ui.PaneRepoList)
 			} else if m.repoList.Focused {
-				if len(m.prs) > 0 {
-					m.repoList.Focused = false
-					m.prList.Focused = true
-				}
+				m.focus(ui.PanePRList)
 			}
 			return m, nil
 		}
 
+		if key.Matches(msg, m.keys.Create) && (m.prList.Focused || m.repoList.Focused) {
+			return m, m.openCreateForm()
+		}
+
 		if m.prList.Focused && !m.loadingPRs && len(m.prs) > 0 {
-			if key.Matches(msg, upKeys) {
+			if key.Matches(msg, m.keys.Up) {
 				m.prList.MoveUp()
-				selected := m.prList.GetSelected()
-				if selected != nil {
-					m.prDetail.SetPR(selected)
-				}
+				m.syncDetail()
 				return m, nil
 			}
 
-			if key.Matches(msg, downKeys) {
+			if key.Matches(msg, m.keys.Down) {
 				m.prList.MoveDown()
-				selected := m.prList.GetSelected()
-				if selected != nil {
-					m.prDetail.SetPR(selected)
-				}
+				m.syncDetail()
 				return m, nil
 			}
 
-			if key.Matches(msg, enterKeys) {
-				selected := m.prList.GetSelected()
-				if selected != nil {
-					if err := utils.OpenBrowser(selected.Links.HTML.Href); err != nil {
-						m.err = err
-					}
-				}
+			if key.Matches(msg, m.keys.Enter) {
+				m.openSelectedPR()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.OpenLinks) {
+				return m, m.openLinkPicker()
+			}
+
+			if key.Matches(msg, m.keys.Yank) {
+				m.openYankMenu()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.Merge) {
+				return m, m.openMergeDialog()
+			}
+
+			if key.Matches(msg, m.keys.Edit) {
+				return m, m.openEditForm()
+			}
+
+			if key.Matches(msg, m.keys.Comment) {
+				return m, m.writeDraft(draftComment)
+			}
+
+			if key.Matches(msg, m.keys.Decline) {
+				return m, m.writeDraft(draftDecline)
+			}
+
+			if key.Matches(msg, m.keys.SortNext) {
+				m.prList.CycleSort()
+				m.saveSort()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.SortReverse) {
+				m.prList.ReverseSort()
+				m.saveSort()
 				return m, nil
 			}
 		}
 
-		if m.repoList.Focused && len(m.repos) > 0 {
-			if key.Matches(msg, upKeys) {
+		if m.repoList.Focused && m.repoList.Len() > 0 {
+			if key.Matches(msg, m.keys.Up) {
 				m.repoList.MoveUp()
 				return m, nil
 			}
 
-			if key.Matches(msg, downKeys) {
+			if key.Matches(msg, m.keys.Down) {
 				m.repoList.MoveDown()
 				return m, nil
 			}
 
-			if key.Matches(msg, enterKeys) {
-				selected := m.repoList.GetSelected()
-				if selected != nil {
-					m.selectedRepo = selected
-					m.repoList.SetSelected(m.repoList.Cursor)
-					m.lastRequestedRepo = selected.Slug
-					m.loadingPRs = true
-					return m, fetchPRsCmd(m.client, selected.Slug)
-				}
+			if key.Matches(msg, m.keys.Enter) {
+				cmd := m.loadSelectedRepo()
+				return m, cmd
+			}
+
+			if key.Matches(msg, m.keys.SortNext) {
+				m.repoList.CycleSort()
 				return m, nil
 			}
+
+			if key.Matches(msg, m.keys.CycleProject) {
+				m.repoList.CycleProject()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.TogglePin) {
+				m.togglePin()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.CycleRepoRole) {
+				m.repoRole = nextRole(m.repoRole)
+				m.repoList.Role = m.repoRole
+				return m, fetchReposCmd(m.client, m.repoRole)
+			}
 		}
 
 		if m.prDetail.Focused && !m.loadingPRs {
-			if key.Matches(msg, upKeys) {
+			if key.Matches(msg, m.keys.Up) {
 				m.prDetail.ScrollUp()
 				return m, nil
 			}
 
-			if key.Matches(msg, downKeys) {
+			if key.Matches(msg, m.keys.Down) {
 				m.prDetail.ScrollDown()
 				return m, nil
 			}
 
-			if key.Matches(msg, halfScrollUpKeys) {
+			if key.Matches(msg, m.keys.HalfPageUp) {
 				m.prDetail.ScrollUpHalf()
 				return m, nil
 			}
 
-			if key.Matches(msg, halfScrollDownKeys) {
+			if key.Matches(msg, m.keys.HalfPageDown) {
 				m.prDetail.ScrollDownHalf()
 				return m, nil
 			}
+
+			if key.Matches(msg, m.keys.Search) {
+				return m, m.prDetail.StartSearch()
+			}
+
+			if key.Matches(msg, m.keys.OpenLinks) {
+				return m, m.openLinkPicker()
+			}
+
+			if key.Matches(msg, m.keys.Yank) {
+				m.openYankMenu()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.Merge) {
+				return m, m.openMergeDialog()
+			}
+
+			if key.Matches(msg, m.keys.Edit) {
+				return m, m.openEditForm()
+			}
+
+			if key.Matches(msg, m.keys.Comment) {
+				return m, m.writeDraft(draftComment)
+			}
+
+			if key.Matches(msg, m.keys.Decline) {
+				return m, m.writeDraft(draftDecline)
+			}
+
+			if key.Matches(msg, m.keys.NextMatch) {
+				m.prDetail.NextMatch()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.NextTab) {
+				m.prDetail.NextTab()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.PrevTab) {
+				m.prDetail.PrevTab()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.Enter) && m.prDetail.Tab == ui.TabCommits {
+				return m, m.openCommitDiff()
+			}
+
+			if m.prDetail.Tab == ui.TabTasks {
+				if key.Matches(msg, m.keys.ToggleTask) {
+					if task := m.prDetail.SelectedTask(); task != nil {
+						return m, toggleTaskCmd(m.client, *m.prDetail.PR, *task)
+					}
+					return m, nil
+				}
+
+				if key.Matches(msg, m.keys.NewTask) {
+					return m, m.prDetail.StartTask("", 0)
+				}
+
+				if key.Matches(msg, m.keys.CommentTask) {
+					m.comments.Open(nil)
+					m.comments.Loading = true
+					return m, fetchCommentsCmd(m.client, *m.prDetail.PR)
+				}
+			}
+
+			if key.Matches(msg, m.keys.Collapse) && m.prDetail.Tab == ui.TabActivity {
+				m.openCollapseMenu()
+				return m, nil
+			}
+
+			if key.Matches(msg, m.keys.PrevMatch) {
+				m.prDetail.PrevMatch()
+				return m, nil
+			}
+		}
+
+		return m, nil
+
+	case userMsg:
+		if msg.user == nil {
+			return m, nil
 		}
 
+		m.user = msg.user
+		m.statusBar.User = msg.user.FullName
+		m.statusBar.Nickname = msg.user.Nickname
+
+		relations := make(map[string]ui.Relation, len(m.prs))
+		for _, pr := range m.prs {
+			uiPR := toUIPR(pr, msg.user.UUID)
+			relations[uiPR.Key()] = uiPR.Relation
+		}
+		m.prList.SetRelations(relations)
+		m.syncDetail()
 		return m, nil
 
 	case reposMsg:
+		if msg.role != m.repoRole {
+			return m, nil
+		}
+
 		m.repos = msg.repos
 		m.repoList.SetRepositories(msg.repos)
+		countCmd := fetchOpenPRCountsCmd(m.client, msg.role, msg.repos)
+
+		// Only the first load picks a repository, later ones come from switching roles
+		if m.lastRequestedRepo != "" {
+			return m, countCmd
+		}
+
+		if cmd := m.restoreSession(); cmd != nil {
+			return m, tea.Batch(cmd, countCmd)
+		}
 
-		if len(msg.repos) > 0 {
-			m.selectedRepo = &msg.repos[0]
-			m.repoList.SetSelected(0)
-			m.lastRequestedRepo = msg.repos[0].Slug
+		if len(m.repoList.Repositories) > 0 {
+			first := m.repoList.Repositories[0]
+			m.selectedRepo = &first
+			m.repoList.SelectRepo(first.Slug)
+			m.lastRequestedRepo = first.Slug
 			m.loadingPRs = true
-			return m, fetchPRsCmd(m.client, msg.repos[0].Slug)
+			fetchCmd := m.fetchSource(first.Slug)
+			return m, tea.Batch(fetchCmd, countCmd)
 		}
 
 		m.loading = false
+		return m, countCmd
+
+	case activityMsg:
+		m.prDetail.SetActivity(msg.version, msg.events, msg.err)
+		return m, nil
+
+	case commitsMsg:
+		m.prDetail.SetCommits(msg.version, msg.commits, msg.err)
+		return m, nil
+
+	case diffMsg:
+		if m.diff.Visible && msg.spec == m.diff.Spec {
+			m.diff.SetDiff(msg.diff, msg.err)
+		}
+		return m, nil
+
+	case tasksMsg:
+		m.prDetail.SetTasks(msg.version, msg.tasks, msg.err)
+		if msg.err == nil {
+			m.prList.SetTaskCounts(map[string]int{msg.prKey: ui.OpenTasks(msg.tasks)})
+		}
+		return m, nil
+
+	case taskSavedMsg:
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		m.prDetail.Reload(ui.TabTasks, msg.version)
+		return m, m.flash(msg.message)
+
+	case commentsMsg:
+		if !m.comments.Visible || m.prDetail.PR == nil || msg.prKey != m.prDetail.PR.Key() {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.comments.Close()
+			return m, m.flash(msg.err.Error())
+		}
+		m.comments.Open(commentItems(msg.comments))
+		return m, nil
+
+	case checksMsg:
+		m.prDetail.SetChecks(msg.version, msg.checks, msg.err)
+		if m.merge.Visible && m.merge.PR.Version() == msg.version {
+			m.merge.SetChecks(msg.checks, msg.err)
+		}
+		return m, nil
+
+	case mergedMsg:
+		m.merge.Close()
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		reload := m.reload()
+		return m, tea.Batch(m.flash(fmt.Sprintf("Merged #%d", msg.id)), reload)
+
+	case ui.EditDescriptionMsg:
+		if m.edit.Visible {
+			return m, prepareDraftCmd(m.client, m.edit.Target(), draftDescription, msg.Description)
+		}
+		return m, nil
+
+	case ui.BranchesChangedMsg:
+		if m.edit.Visible && m.edit.Creating() {
+			return m, fetchBranchCommitsCmd(m.client, m.edit.Repo, msg)
+		}
+		return m, nil
+
+	case branchCommitsMsg:
+		if m.edit.Visible {
+			m.edit.SetCommits(msg.source, msg.destination, msg.commits)
+		}
+		return m, nil
+
+	case draftMsg:
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		return m, openEditorCmd(msg)
+
+	case editorMsg:
+		return m, m.useDraft(msg)
+
+	case prActionMsg:
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		if msg.reload {
+			reload := m.reload()
+			return m, tea.Batch(m.flash(msg.message), reload)
+		}
+		m.prDetail.Reload(ui.TabActivity, msg.version)
+		return m, m.flash(msg.message)
+
+	case issueMsg:
+		m.prDetail.SetIssue(msg.key, msg.issue, msg.err)
+		return m, nil
+
+	case editDataMsg:
+		if m.edit.Visible && msg.prKey == m.edit.Target().Key() {
+			return m, m.edit.Load(msg.data, msg.err)
+		}
+		return m, nil
+
+	case prSavedMsg:
+		var conflict *api.ConflictError
+		switch {
+		case errors.As(msg.err, &conflict):
+			m.edit.SetConflict(msg.err)
+			return m, nil
+		case msg.err != nil:
+			m.edit.SetError(msg.err)
+			return m, nil
+		}
+		m.edit.Close()
+		if msg.created {
+			reload := m.reload()
+			return m, tea.Batch(m.flash(fmt.Sprintf("Created #%d", msg.id)), reload)
+		}
+		reload := m.reload()
+		return m, tea.Batch(m.flash(fmt.Sprintf("Updated #%d", msg.id)), reload)
+
+	case clearFlashMsg:
+		if msg.id == m.flashID {
+			m.statusBar.Message = ""
+		}
+		return m, nil
+
+	case copiedMsg:
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		return m, m.flash("Copied " + msg.what + ": " + msg.text)
+
+	case linksMsg:
+		if !m.links.Visible || msg.prKey != m.links.PRKey {
+			return m, nil
+		}
+		m.links.SetLinks(msg.links)
+		if msg.err != nil {
+			return m, m.flash(msg.err.Error())
+		}
+		return m, nil
+
+	case openPRCountsMsg:
+		if msg.role == m.repoRole {
+			m.repoList.SetOpenPRCounts(msg.counts)
+		}
 		return m, nil
 
 	case statusMsg:
@@ -339,49 +810,62 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		m.loadingPRs = false
-		m.loading = false
-		m.prs = msg.prs
-
-		internalPRs := make([]ui.PR, len(msg.prs))
-		for i, pr := range msg.prs {
-			authorName := pr.Author.FullName
-			if authorName == "" {
-				authorName = pr.Author.Username
-			}
-
-			// Extract workspace and repo from full_name (format: workspace/repo)
-			workspace := ""
-			repo := ""
-			if pr.Source.Repository.FullName != "" {
-				parts := strings.Split(pr.Source.Repository.FullName, "/")
-				if len(parts) >= 2 {
-					workspace = parts[0]
-					repo = parts[1]
-				}
-			}
+		return m, m.showPRs(msg.repoSlug, msg.prs, true)
 
-			internalPRs[i] = ui.PR{
-				ID:          pr.ID,
-				Title:       pr.Title,
-				Description: pr.Description,
-				Author:      authorName,
-				State:       pr.State,
-				CreatedOn:   pr.CreatedOn.Format(time.DateTime),
-				UpdatedOn:   pr.UpdatedOn.Format(time.DateTime),
-				Workspace:   workspace,
-				Repo:        repo,
-				Links: ui.Links{
-					HTML: ui.HTML{
-						Href: pr.Links.HTML.Href,
-					},
-				},
-			}
+	case fanOutStartedMsg:
+		f := m.fanOutFor(msg.id)
+		if f == nil {
+			return m, nil
 		}
+		f.results = msg.results
+		m.prList.Progress = f.progress()
+		return m, waitForRepoCmd(f.id, f.results)
 
-		m.prList.SetPRs(internalPRs)
-		if len(internalPRs) > 0 {
-			m.prDetail.SetPR(&internalPRs[0])
+	case repoFetchedMsg:
+		f := m.fanOutFor(msg.id)
+		if f == nil {
+			return m, nil
+		}
+
+		f.done++
+		if msg.result.Err != nil {
+			f.failed[msg.result.Item] = msg.result.Err
+		} else {
+			f.prs = append(f.prs, msg.result.Value...)
+		}
+		m.prList.Progress = f.progress()
+
+		// Show what has arrived so far, once there is something to show
+		var cmd tea.Cmd
+		if len(f.prs) > 0 {
+			prs := slices.Clone(f.prs)
+			api.SortByUpdated(prs)
+			cmd = m.showPRs(f.source, prs, false)
+		}
+		return m, tea.Batch(cmd, waitForRepoCmd(f.id, f.results))
+
+	case fanOutDoneMsg:
+		f := m.fanOutFor(msg.id)
+		if f == nil {
+			return m, nil
+		}
+		m.stopFanOut()
+
+		api.SortByUpdated(f.prs)
+		cmds := []tea.Cmd{m.showPRs(f.source, f.prs, true)}
+		if len(f.failed) > 0 {
+			m.prList.Progress = fmt.Sprintf("%d of %d %s failed", len(f.failed), f.total, f.unit)
+			cmds = append(cmds, m.flash(failedReposMessage(f.failed)))
+		}
+		return m, tea.Batch(cmds...)
+
+	case buildStatusesMsg:
+		if msg.repoSlug != m.lastRequestedRepo {
+			return m, nil
+		}
+		m.prList.SetBuildStatuses(msg.statuses)
+		if msg.failed > 0 {
+			return m, m.flash(fmt.Sprintf("Failed to fetch the build status of %d PRs", msg.failed))
 		}
 		return m, nil
 
@@ -397,53 +881,439 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// toUIPR converts an API pull request into the form shown by the UI components.
+// userUUID is the logged-in user, empty while still unknown
+func toUIPR(pr api.PR, userUUID string) ui.PR {
+	authorName := pr.Author.FullName
+	if authorName == "" {
+		authorName = pr.Author.Username
+	}
+
+	// Extract workspace and repo from full_name (format: workspace/repo).
+	// The destination is where the PR lives, the source may be a fork
+	fullName := pr.Destination.Repository.FullName
+	if fullName == "" {
+		fullName = pr.Source.Repository.FullName
+	}
+	workspace := ""
+	repo := ""
+	if parts := strings.Split(fullName, "/"); len(parts) >= 2 {
+		workspace = parts[0]
+		repo = parts[1]
+	}
+
+	approvals := 0
+	for _, participant := range pr.Participants {
+		if participant.Approved {
+			approvals++
+		}
+	}
+
+	return ui.PR{
+		ID:           pr.ID,
+		Title:        pr.Title,
+		Description:  pr.Description,
+		Author:       authorName,
+		State:        pr.State,
+		CreatedOn:    pr.CreatedOn.Format(time.DateTime),
+		UpdatedOn:    pr.UpdatedOn.Format(time.DateTime),
+		CreatedAt:    pr.CreatedOn,
+		UpdatedAt:    pr.UpdatedOn,
+		Workspace:    workspace,
+		Repo:         repo,
+		SourceBranch: pr.Source.Branch.Name,
+		SourceRepo:   pr.Source.Repository.FullName,
+		DestBranch:   pr.Destination.Branch.Name,
+		Approvals:    approvals,
+		Reviewers:    len(pr.Reviewers),
+		CommentCount: pr.CommentCount,
+		TaskCount:    pr.TaskCount,
+		Relation:     relationOf(pr, userUUID),
+		Links: ui.Links{
+			HTML: ui.HTML{
+				Href: pr.Links.HTML.Href,
+			},
+		},
+	}
+}
+
+// relationOf tells how the user is involved in a PR
+func relationOf(pr api.PR, userUUID string) ui.Relation {
+	switch {
+	case userUUID == "":
+		return ui.RelationNone
+	case pr.Author.UUID == userUUID:
+		return ui.RelationAuthored
+	case pr.ApprovedBy(userUUID):
+		return ui.RelationApproved
+	case pr.HasReviewer(userUUID) && pr.State == "OPEN":
+		return ui.RelationReviewRequested
+	default:
+		return ui.RelationNone
+	}
+}
+
+// userUUID is the logged-in user's UUID, empty until it has been fetched
+func (m model) userUUID() string {
+	if m.user == nil {
+		return ""
+	}
+	return m.user.UUID
+}
+
+// nextRole returns the repository role scope after role
+func nextRole(role string) string {
+	for i, r := range api.RepoRoles {
+		if r == role {
+			return api.RepoRoles[(i+1)%len(api.RepoRoles)]
+		}
+	}
+	return api.RepoRoles[0]
+}
+
+// focus moves keyboard focus to a pane, ignoring lists that have nothing to show
+func (m *model) focus(pane ui.Pane) {
+	switch pane {
+	case ui.PanePRList:
+		if len(m.prs) == 0 {
+			return
+		}
+	case ui.PaneRepoList:
+		if m.repoList.Len() == 0 {
+			return
+		}
+	}
+
+	m.prList.Focused = pane == ui.PanePRList
+	m.prDetail.Focused = pane == ui.PaneDetail
+	m.repoList.Focused = pane == ui.PaneRepoList
+
+	// Bring the pane into view when the layout hides it
+	if pane != ui.PaneDetail && (pane != m.listPane || m.layout.Zoom) {
+		m.listPane = pane
+		m.layout.Zoom = false
+		m.resize()
+	}
+}
+
+// paneRects places the panes for the current terminal size and layout,
+// leaving the bottom line to the footer
+func (m model) paneRects() map[ui.Pane]ui.Rect {
+	return m.layout.Arrange(m.width, m.height-1, m.listPane)
+}
+
+// resize sizes the components to the areas the layout gives them.
+// Component sizes exclude the 1-cell border on each side
+func (m *model) resize() {
+	for pane, r := range m.paneRects() {
+		switch pane {
+		case ui.PanePRList:
+			m.prList.Width, m.prList.Height = r.W-2, r.H-2
+		case ui.PaneRepoList:
+			m.repoList.Width, m.repoList.Height = r.W-2, r.H-2
+		case ui.PaneDetail:
+			m.prDetail.Width, m.prDetail.Height = r.W-2, r.H-2
+		}
+	}
+
+	// Keep the cursors visible in the resized lists
+	m.prList.SetCursor(m.prList.Cursor)
+	m.repoList.SetCursor(m.repoList.Cursor)
+}
+
+// syncDetail shows the PR under the list cursor in the detail pane
+func (m *model) syncDetail() {
+	if selected := m.prList.GetSelected(); selected != nil {
+		m.prDetail.SetPR(selected)
+	}
+}
+
+func (m *model) openSelectedPR() {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return
+	}
+	m.openURL(selected.Links.HTML.Href)
+}
+
+// reload fetches the PRs of the current source again, e.g. after a change
+func (m *model) reload() tea.Cmd {
+	if m.loadingPRs {
+		return nil
+	}
+	m.loadingPRs = true
+	m.prDetail.Invalidate()
+	return m.fetchSource(m.lastRequestedRepo)
+}
+
+// flash shows a message in the status bar for a few seconds
+func (m *model) flash(message string) tea.Cmd {
+	m.flashID++
+	m.statusBar.Message = message
+	id := m.flashID
+	return tea.Tick(flashDuration, func(time.Time) tea.Msg {
+		return clearFlashMsg{id: id}
+	})
+}
+
+// loadSelectedRepo marks the repo or dashboard under the cursor as selected and fetches its PRs
+func (m *model) loadSelectedRepo() tea.Cmd {
+	if view := m.repoList.GetSelectedView(); view != nil {
+		m.selectedRepo = nil
+		m.repoList.SetSelected(m.repoList.Cursor)
+		m.lastRequestedRepo = view.ID
+		m.loadingPRs = true
+		return m.fetchSource(view.ID)
+	}
+
+	selected := m.repoList.GetSelected()
+	if selected == nil {
+		return nil
+	}
+
+	m.selectedRepo = selected
+	m.repoList.SetSelected(m.repoList.Cursor)
+	m.lastRequestedRepo = selected.Slug
+	m.loadingPRs = true
+
+	m.state.Visit(m.fullName(selected.Slug))
+	m.repoList.SetRecent(m.workspaceRepos(m.state.Recent))
+	m.saveState()
+
+	return m.fetchSource(selected.Slug)
+}
+
+// focusedPane reports which pane currently receives navigation keys
+func (m model) focusedPane() ui.Pane {
+	switch {
+	case m.prDetail.Focused:
+		return ui.PaneDetail
+	case m.repoList.Focused:
+		return ui.PaneRepoList
+	default:
+		return ui.PanePRList
+	}
+}
+
+// overlayVisible reports whether a dialog covers the panes, in which case
+// mouse events are ignored
+func (m model) overlayVisible() bool {
+	return m.help.Visible || m.links.Visible || m.yank.Visible || m.collapse.Visible ||
+		m.diff.Visible || m.comments.Visible || m.urlModal.Visible || m.merge.Visible || m.edit.Visible
+}
+
 func (m model) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("\n\n  Error: %s\n\n", m.err.Error())
 	}
 
 	if m.loading {
-		str := fmt.Sprintf("\n\n   %s Loading... %s\n\n", m.spinner.View(), quitKeys.Help().Desc)
+		str := fmt.Sprintf("\n\n   %s Loading... %s to quit\n\n", m.spinner.View(), m.keys.Quit.Help().Key)
 		if m.quitting {
 			return str + "\n"
 		}
 		return str
 	}
 
+	if m.help.Visible {
+		return m.help.View()
+	}
+
+	if m.edit.Visible {
+		return m.edit.View()
+	}
+
+	if m.links.Visible {
+		return m.links.View(m.help.ShortView(ui.PaneLinks, m.width))
+	}
+
+	if m.yank.Visible {
+		return m.yank.View(m.help.ShortView(ui.PaneMenu, m.width))
+	}
+
+	if m.comments.Visible {
+		return m.comments.View(m.help.ShortView(ui.PaneMenu, m.width))
+	}
+
+	if m.diff.Visible {
+		return m.diff.View(m.help.ShortView(ui.PaneDiff, m.width-6))
+	}
+
+	if m.collapse.Visible {
+		return m.collapse.View(m.help.ShortView(ui.PaneMenu, m.width))
+	}
+
+	if m.urlModal.Visible {
+		return m.urlModal.View(m.help.ShortView(ui.PaneURL, m.width))
+	}
+
+	if m.merge.Visible {
+		return m.merge.View(m.help.ShortView(ui.PaneMerge, m.width))
+	}
+
 	prListView := m.prList.View()
 	repoListView := m.repoList.View()
 	detailView := m.prDetail.View()
 
 	if m.loadingPRs {
+		loadingText := m.spinner.View() + " Loading PRs..."
+		if m.prList.Progress != "" {
+			loadingText += " " + m.prList.Progress
+		}
 		prListView = lipgloss.NewStyle().
 			Width(m.prList.Width).
 			Height(m.prList.Height).
 			Align(lipgloss.Center, lipgloss.Center).
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#565f89")).
-			Render(fmt.Sprintf("%s Loading PRs...", m.spinner.View()))
+			BorderForeground(ui.ActiveTheme().Border).
+			Render(loadingText)
 	}
 
-	leftPanel := lipgloss.JoinVertical(lipgloss.Top, prListView, repoListView)
+	var panels string
+	switch {
+	case m.layout.Zoom:
+		panels = detailView
+	case m.layout.Effective(m.width) == ui.LayoutStacked:
+		listView := prListView
+		if m.listPane == ui.PaneRepoList {
+			listView = repoListView
+		}
+		panels = lipgloss.JoinVertical(lipgloss.Left, listView, detailView)
+	default:
+		leftPanel := lipgloss.JoinVertical(lipgloss.Top, prListView, repoListView)
+		panels = lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, detailView)
+	}
+
+	hints := m.help.ShortView(m.focusedPane(), m.width-lipgloss.Width(m.statusBar.Identity()))
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, detailView)
+	return lipgloss.JoinVertical(lipgloss.Left, panels, m.statusBar.View(hints))
 }
 
 func main() {
-	cfg, err := config.LoadConfig()
+	os.Exit(cli.Execute(runTUI))
+}
+
+// runTUI starts the full-screen interface
+func runTUI(cfg *config.Config) error {
+	keys := ui.DefaultKeyMap()
+	if err := keys.Apply(cfg.Keys); err != nil {
+		return configError(err)
+	}
+	if err := keys.Validate(); err != nil {
+		return configError(err)
+	}
+
+	themes := make(map[string]ui.ThemeSpec, len(cfg.Themes))
+	for name, t := range cfg.Themes {
+		themes[name] = ui.ThemeSpec{Base: t.Base, Glamour: t.Glamour, Colors: t.Colors}
+	}
+	theme, err := ui.ResolveTheme(cfg.Theme, themes)
+	if err != nil {
+		return configError(err)
+	}
+	ui.SetTheme(theme)
+
+	switch cfg.Hyperlinks {
+	case "", "auto":
+		ui.SetHyperlinks(utils.SupportsHyperlinks())
+	case "always":
+		ui.SetHyperlinks(true)
+	case "never":
+		ui.SetHyperlinks(false)
+	default:
+		return configError(fmt.Errorf("invalid hyperlinks %q (expected auto, always or never)", cfg.Hyperlinks))
+	}
+
+	columns, err := ui.ParseColumns(cfg.Columns)
+	if err != nil {
+		return configError(err)
+	}
+
+	st, err := state.Load()
 	if err != nil {
-		fmt.Printf("Configuration error: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("state error: %w", err)
 	}
 
 	client := api.NewClient(cfg.Email, cfg.APIToken, cfg.Workspace, cfg.Repo)
 
-	m := initialModel()
-	m.client = client
+	role := cfg.RepoRole
+	if role == "" {
+		role = "member"
+	}
+	if !slices.Contains(api.RepoRoles, role) {
+		return configError(fmt.Errorf("invalid repo_role %q (expected %s)", role, strings.Join(api.RepoRoles, ", ")))
+	}
+
+	layout := ui.NewLayout()
+	if layout.Mode, err = ui.ParseLayoutMode(cfg.Layout.Mode); err != nil {
+		return configError(err)
+	}
+	if cfg.Layout.Split != 0 {
+		layout.SetSplit(cfg.Layout.Split)
+	}
+	if cfg.Layout.StackBelow != 0 {
+		layout.StackBelow = cfg.Layout.StackBelow
+	}
+
+	if !slices.Contains([]string{"", "merge_commit", "squash", "fast_forward"}, cfg.Merge.Strategy) {
+		return configError(fmt.Errorf("invalid merge strategy %q (expected merge_commit, squash or fast_forward)", cfg.Merge.Strategy))
+	}
+
+	templates := make([]config.TemplateConfig, len(cfg.Templates))
+	for i, t := range cfg.Templates {
+		if t.Name == "" {
+			return configError(fmt.Errorf("template %d has no name", i+1))
+		}
+		body, err := t.Text()
+		if err != nil {
+			return configError(err)
+		}
+		templates[i] = t
+		templates[i].Body = body
+	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	if err := ui.SetJiraKeyPattern(cfg.Jira.KeyPattern); err != nil {
+		return configError(err)
+	}
+	if cfg.Jira.FetchIssues && cfg.Jira.BaseURL == "" {
+		return configError(errors.New("jira fetch_issues requires a base_url"))
+	}
+	if cfg.Jira.FetchIssues && (cfg.Jira.Email == "" || cfg.Jira.APIToken == "") {
+		return configError(errors.New("jira fetch_issues requires JIRA_EMAIL and JIRA_TOKEN"))
+	}
+
+	views, sidebar, err := resolveViews(cfg.Views, cfg.Teams)
+	if err != nil {
+		return configError(err)
+	}
+
+	m := initialModel(&keys, columns, st, role)
+	m.layout = layout
+	m.jiraURL = cfg.Jira.BaseURL
+	m.prDetail.JiraURL = cfg.Jira.BaseURL
+	if cfg.Jira.FetchIssues {
+		m.tracker = tracker.NewJira(cfg.Jira.BaseURL, cfg.Jira.Email, cfg.Jira.APIToken)
+	}
+	m.opener = utils.Opener{Command: cfg.OpenCommand}
+	m.merge.AllowOverride = cfg.Merge.AllowOverride
+	m.mergeOptions = api.MergeOptions{Strategy: cfg.Merge.Strategy, CloseSourceBranch: cfg.Merge.CloseSourceBranch}
+	m.templates = templates
+	m.client = client
+	m.repoList.Project = cfg.Project
+	m.repoList.Views = append(m.repoList.Views, sidebar...)
+	m.views = views
+	m.repoList.SetPinned(m.workspaceRepos(st.Pinned))
+	m.repoList.SetRecent(m.workspaceRepos(st.Recent))
+	m.statusBar.Workspace = cfg.Workspace
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
+
+	return nil
+}
+
+func configError(err error) error {
+	return cli.WithExitCode(cli.ExitConfig, fmt.Errorf("configuration error: %w", err))
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+)
+
+type checksMsg struct {
+	version string
+	checks  []ui.MergeCheck
+	err     error
+}
+
+type mergedMsg struct {
+	id  int
+	err error
+}
+
+// fetchChecksCmd evaluates whether a PR is ready to be merged
+func fetchChecksCmd(client *api.Client, pr ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		checks, err := client.MergeReadiness(pr.Repo, pr.ID)
+		if err != nil {
+			return checksMsg{version: pr.Version(), err: err}
+		}
+
+		statuses := map[api.CheckStatus]ui.CheckStatus{
+			api.CheckPassed:  ui.CheckPassed,
+			api.CheckFailed:  ui.CheckFailed,
+			api.CheckUnknown: ui.CheckUnknown,
+			api.CheckPending: ui.CheckPending,
+		}
+		uiChecks := make([]ui.MergeCheck, len(checks))
+		for i, c := range checks {
+			uiChecks[i] = ui.MergeCheck{Name: c.Name, Status: statuses[c.Status], Detail: c.Detail}
+		}
+		return checksMsg{version: pr.Version(), checks: uiChecks}
+	}
+}
+
+// mergeCmd merges a PR
+func mergeCmd(client *api.Client, pr ui.PR, opts api.MergeOptions) tea.Cmd {
+	return func() tea.Msg {
+		return mergedMsg{id: pr.ID, err: client.MergePR(pr.Repo, pr.ID, opts)}
+	}
+}
+
+// openMergeDialog asks to confirm merging the selected PR, evaluating its
+// merge checks first
+func (m *model) openMergeDialog() tea.Cmd {
+	selected := m.prList.GetSelected()
+	if selected == nil {
+		return nil
+	}
+	if selected.State != "OPEN" {
+		return m.flash("Only open PRs can be merged")
+	}
+
+	m.merge.Open(*selected)
+	return fetchChecksCmd(m.client, *selected)
+}
+
+// updateMergeDialog handles key presses while a merge is being confirmed.
+// Failed checks disable the merge unless overriding is allowed
+func (m *model) updateMergeDialog(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Close):
+		if !m.merge.Merging {
+			m.merge.Close()
+		}
+	case key.Matches(msg, m.keys.Confirm) && m.merge.CanMerge(),
+		key.Matches(msg, m.keys.Override) && m.merge.CanOverride():
+		m.merge.Merging = true
+		return mergeCmd(m.client, *m.merge.PR, m.mergeOptions)
+	}
+	return nil
+}
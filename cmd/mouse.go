@@ -0,0 +1,148 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+)
+
+// doubleClickInterval is the longest gap between two clicks on the same row
+// that still counts as a double-click
+const doubleClickInterval = 400 * time.Millisecond
+
+// wheelDetailLines is how far one wheel notch scrolls the detail pane
+const wheelDetailLines = 3
+
+// click remembers the previous left click to detect double-clicks
+type click struct {
+	at   time.Time
+	pane ui.Pane
+	row  int
+}
+
+// paneAt returns the pane under a screen position and its bounds
+func (m model) paneAt(x, y int) (ui.Pane, ui.Rect, bool) {
+	for pane, r := range m.paneRects() {
+		if r.Contains(x, y) {
+			return pane, r, true
+		}
+	}
+	return 0, ui.Rect{}, false
+}
+
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.overlayVisible() || m.loading {
+		return m, nil
+	}
+
+	pane, bounds, ok := m.paneAt(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		if msg.Action == tea.MouseActionPress {
+			m.scroll(pane, msg.Button == tea.MouseButtonWheelUp)
+		}
+		return m, nil
+
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		return m, m.leftClick(pane, msg.X-bounds.X, msg.Y-bounds.Y)
+	}
+
+	return m, nil
+}
+
+// scroll moves the list cursor or the detail view of the pane under the wheel
+func (m *model) scroll(pane ui.Pane, up bool) {
+	switch pane {
+	case ui.PanePRList:
+		if m.loadingPRs || len(m.prs) == 0 {
+			return
+		}
+		if up {
+			m.prList.MoveUp()
+		} else {
+			m.prList.MoveDown()
+		}
+		m.syncDetail()
+
+	case ui.PaneRepoList:
+		if up {
+			m.repoList.MoveUp()
+		} else {
+			m.repoList.MoveDown()
+		}
+
+	case ui.PaneDetail:
+		for range wheelDetailLines {
+			if up {
+				m.prDetail.ScrollUp()
+			} else {
+				m.prDetail.ScrollDown()
+			}
+		}
+	}
+}
+
+// leftClick focuses the clicked pane and acts on the row or link under the
+// pointer. x and y are relative to the pane's top-left border corner
+func (m *model) leftClick(pane ui.Pane, x, y int) tea.Cmd {
+	if m.loadingPRs && pane != ui.PaneRepoList {
+		return nil
+	}
+
+	m.focus(pane)
+
+	switch pane {
+	case ui.PanePRList:
+		row := m.prList.RowAt(y)
+		if row < 0 {
+			return nil
+		}
+		m.prList.SetCursor(row)
+		m.syncDetail()
+		if m.isDoubleClick(pane, row) {
+			m.openSelectedPR()
+		}
+
+	case ui.PaneRepoList:
+		row := m.repoList.RowAt(y)
+		if row < 0 {
+			return nil
+		}
+		m.repoList.SetCursor(row)
+		if m.isDoubleClick(pane, row) {
+			return m.loadSelectedRepo()
+		}
+
+	case ui.PaneDetail:
+		if link := m.prDetail.LinkAt(x, y); link != "" {
+			m.openURL(link)
+		}
+	}
+
+	return nil
+}
+
+// isDoubleClick records a click on a row and reports whether it completes a double-click
+func (m *model) isDoubleClick(pane ui.Pane, row int) bool {
+	now := time.Now()
+	double := m.lastClick.pane == pane &&
+		m.lastClick.row == row &&
+		now.Sub(m.lastClick.at) <= doubleClickInterval
+
+	if double {
+		// Reset so a third click starts a new double-click
+		m.lastClick = click{}
+	} else {
+		m.lastClick = click{at: now, pane: pane, row: row}
+	}
+	return double
+}
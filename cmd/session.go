@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/state"
+)
+
+// sortKey identifies the current repository or dashboard in the persisted sort orders
+func (m model) sortKey() string {
+	return m.client.Workspace() + "/" + m.lastRequestedRepo
+}
+
+// restoreSession loads the repository or dashboard open when lazy-bb last
+// exited, returning nil when there is nothing to restore
+func (m *model) restoreSession() tea.Cmd {
+	session, ok := m.state.SessionFor(m.client.Workspace())
+	if !ok {
+		return nil
+	}
+
+	if isSidebarView(session.Source) {
+		if !m.repoList.SelectView(session.Source) {
+			return nil
+		}
+	} else {
+		if !m.repoList.SelectRepo(session.Source) {
+			return nil
+		}
+		m.selectedRepo = m.repoList.GetSelected()
+	}
+
+	m.restore = &session
+	m.lastRequestedRepo = session.Source
+	m.loadingPRs = true
+	return m.fetchSource(session.Source)
+}
+
+// saveSession remembers the loaded source, PR under the cursor and focused pane
+func (m *model) saveSession() {
+	if m.lastRequestedRepo == "" {
+		return
+	}
+
+	session := state.Session{Source: m.lastRequestedRepo, Pane: m.focusedPane().String()}
+	if selected := m.prList.GetSelected(); selected != nil {
+		session.PR = selected.Key()
+	}
+
+	m.state.SetSession(m.client.Workspace(), session)
+	m.saveState()
+}
+
+// togglePin stars or unstars the repository under the cursor
+func (m *model) togglePin() {
+	selected := m.repoList.GetSelected()
+	if selected == nil {
+		return
+	}
+
+	m.state.TogglePin(m.fullName(selected.Slug))
+	m.repoList.SetPinned(m.workspaceRepos(m.state.Pinned))
+	m.saveState()
+}
+
+// fullName qualifies a repository slug with the workspace, as stored in the state file
+func (m model) fullName(slug string) string {
+	return m.client.Workspace() + "/" + slug
+}
+
+// workspaceRepos returns the slugs of the full names that belong to the current workspace
+func (m model) workspaceRepos(fullNames []string) []string {
+	prefix := m.client.Workspace() + "/"
+	var slugs []string
+	for _, name := range fullNames {
+		if slug, ok := strings.CutPrefix(name, prefix); ok {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// saveState persists the UI state. Saving is best effort: a failure is shown
+// in the footer until the next message replaces it, and the app keeps running
+func (m *model) saveState() {
+	if err := m.state.Save(); err != nil {
+		m.flashID++
+		m.statusBar.Message = err.Error()
+	}
+}
+
+// saveSort persists the PR list sort order for the current repository
+func (m *model) saveSort() {
+	m.prDetail.SetPR(m.prList.GetSelected())
+
+	m.state.SetSort(m.sortKey(), state.Sort{Column: m.prList.Sort.Column, Desc: m.prList.Sort.Desc})
+	m.saveState()
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/config"
+	"github.com/anasalqoyyum/lazy-bb/internal/ui"
+)
+
+// fanOut is a fetch of a dashboard or view across repositories, running
+// until every repository is done or another source is loaded. A workspace-wide
+// dashboard is a fan-out of a single query
+type fanOut struct {
+	id      int
+	source  string
+	results <-chan api.RepoResult
+	// unit names what is counted in the progress: repos or queries
+	unit  string
+	total int
+	done  int
+	prs   []api.PR
+	// failed holds the error of each repository that could not be fetched
+	failed map[string]error
+}
+
+// progress describes how far the fetch is, e.g. "12/40 repos · 1 failed"
+func (f *fanOut) progress() string {
+	text := fmt.Sprintf("%d/%d %s", f.done, f.total, f.unit)
+	if len(f.failed) > 0 {
+		text += fmt.Sprintf(" · %d failed", len(f.failed))
+	}
+	return text
+}
+
+// fanOutStartedMsg carries the results of a fan-out once its repositories are queried
+type fanOutStartedMsg struct {
+	id      int
+	results <-chan api.RepoResult
+}
+
+// repoFetchedMsg carries the PRs of one repository of a fan-out
+type repoFetchedMsg struct {
+	id     int
+	result api.RepoResult
+}
+
+// fanOutDoneMsg reports that every repository of a fan-out is done
+type fanOutDoneMsg struct {
+	id int
+}
+
+type buildStatusesMsg struct {
+	statuses map[string]string
+	repoSlug string
+	// failed counts the PRs whose statuses could not be fetched
+	failed int
+}
+
+// dashboardPrefix marks PR sources that are dashboards rather than repositories
+const dashboardPrefix = "dashboard:"
+
+// viewPrefix marks PR sources that are saved views from the config file
+const viewPrefix = "view:"
+
+// savedView is a view from the config file, resolved
+type savedView struct {
+	filter api.ViewFilter
+	// sort is the initial order, until the view is sorted otherwise
+	sort ui.SortOrder
+}
+
+// startFanOutCmd queries the repositories of a dashboard or view with a
+// pool of workers, or runs the single query of a workspace-wide dashboard.
+// A dashboard's query is resolved first, as it depends on the user
+func startFanOutCmd(ctx context.Context, client *api.Client, id int, source string, view *savedView, repoSlugs []string) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil {
+			return errMsg(fmt.Errorf("client not initialized"))
+		}
+
+		var query api.RepoQuery
+		if view != nil {
+			query = client.WithContext(ctx).ViewQuery(view.filter)
+		} else {
+			var err error
+			query, err = client.WithContext(ctx).DashboardQuery(api.Dashboard(strings.TrimPrefix(source, dashboardPrefix)))
+			switch {
+			case ctx.Err() != nil:
+				// Another source was loaded meanwhile
+				return nil
+			case err != nil:
+				return errMsg(err)
+			}
+		}
+
+		return fanOutStartedMsg{id: id, results: client.FetchAcross(ctx, repoSlugs, api.DefaultWorkers, query)}
+	}
+}
+
+// waitForRepoCmd waits for the next repository of a fan-out to be done
+func waitForRepoCmd(id int, results <-chan api.RepoResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return fanOutDoneMsg{id: id}
+		}
+		return repoFetchedMsg{id: id, result: result}
+	}
+}
+
+// fetchBuildStatusesCmd summarizes the build statuses of each PR, keyed by ui.PR.Key.
+// PRs may come from several repositories when a dashboard is shown. A PR whose
+// statuses cannot be fetched keeps an unknown status
+func fetchBuildStatusesCmd(ctx context.Context, client *api.Client, source string, prs []ui.PR) tea.Cmd {
+	return func() tea.Msg {
+		summarize := func(c *api.Client, pr ui.PR) (string, error) {
+			statuses, err := c.FetchPRStatuses(pr.Repo, pr.ID)
+			return api.SummarizeStatuses(statuses), err
+		}
+
+		msg := buildStatusesMsg{statuses: make(map[string]string, len(prs)), repoSlug: source}
+		for result := range api.FetchEach(ctx, client, prs, api.DefaultWorkers, summarize) {
+			if result.Err != nil {
+				msg.failed++
+				continue
+			}
+			msg.statuses[result.Item.Key()] = result.Value
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// fetchSource loads the PRs of a repository slug, a dashboard or a saved
+// view, cancelling the dashboard or view still being fetched
+func (m *model) fetchSource(source string) tea.Cmd {
+	m.stopFanOut()
+	if m.cancelSource != nil {
+		m.cancelSource()
+	}
+	m.sourceCtx, m.cancelSource = context.WithCancel(context.Background())
+
+	if !isSidebarView(source) {
+		return fetchPRsCmd(m.client, source)
+	}
+
+	view, isView := m.views[source]
+	unit := "repos"
+	var slugs []string
+	if dashboard := api.Dashboard(strings.TrimPrefix(source, dashboardPrefix)); !isView && dashboard.WorkspaceWide() {
+		// The single query ignores the repository, it is named in failures
+		unit = "queries"
+		slugs = []string{"workspace"}
+	} else {
+		for _, repo := range m.repos {
+			if !isView || view.filter.MatchesRepo(repo.Slug) {
+				slugs = append(slugs, repo.Slug)
+			}
+		}
+	}
+
+	m.fanOutID++
+	m.fanOut = &fanOut{id: m.fanOutID, source: source, unit: unit, total: len(slugs), failed: make(map[string]error)}
+
+	var resolved *savedView
+	if isView {
+		resolved = &view
+	}
+	return startFanOutCmd(m.sourceCtx, m.client, m.fanOutID, source, resolved, slugs)
+}
+
+// showPRs lists the PRs of a source. Partial results of a fan-out keep the
+// cursor on the selected PR, and build statuses are only fetched once final
+func (m *model) showPRs(source string, prs []api.PR, final bool) tea.Cmd {
+	selectedKey := ""
+	if selected := m.prList.GetSelected(); selected != nil && !m.loadingPRs {
+		selectedKey = selected.Key()
+	}
+
+	m.loadingPRs = false
+	m.loading = false
+	m.prs = prs
+
+	internalPRs := make([]ui.PR, len(prs))
+	for i, pr := range prs {
+		internalPRs[i] = toUIPR(pr, m.userUUID())
+	}
+
+	order := m.views[source].sort
+	if saved, ok := m.state.SortFor(m.sortKey()); ok {
+		order = ui.SortOrder{Column: saved.Column, Desc: saved.Desc}
+	}
+	m.prList.Sort = order
+	m.prList.SetPRs(internalPRs)
+	if selectedKey != "" {
+		m.prList.SelectKey(selectedKey)
+	}
+	// The PR to restore may be in a repository that has not arrived yet
+	if m.restore != nil && (m.prList.SelectKey(m.restore.PR) || final) {
+		if pane, ok := ui.ParsePane(m.restore.Pane); ok {
+			m.focus(pane)
+		}
+		m.restore = nil
+	}
+	m.prDetail.SetPR(m.prList.GetSelected())
+
+	if final && m.prList.HasColumn("build") && len(internalPRs) > 0 {
+		return fetchBuildStatusesCmd(m.sourceCtx, m.client, source, internalPRs)
+	}
+	return nil
+}
+
+// failedReposMessage summarizes the repositories a fan-out could not fetch
+func failedReposMessage(failed map[string]error) string {
+	if len(failed) == 1 {
+		for repo, err := range failed {
+			return fmt.Sprintf("Failed to fetch %s: %v", repo, err)
+		}
+	}
+	repos := slices.Sorted(maps.Keys(failed))
+	return fmt.Sprintf("Failed to fetch %d repos: %s", len(repos), strings.Join(repos, ", "))
+}
+
+// stopFanOut forgets the dashboard or view being fetched, if any. Its requests
+// are cancelled with the source's context
+func (m *model) stopFanOut() {
+	m.fanOut = nil
+	m.prList.Progress = ""
+}
+
+// fanOutFor returns the running fan-out with the given ID, nil once it was
+// cancelled or replaced
+func (m *model) fanOutFor(id int) *fanOut {
+	if m.fanOut == nil || m.fanOut.id != id {
+		return nil
+	}
+	return m.fanOut
+}
+
+// isSidebarView reports whether a PR source is listed above the repositories
+func isSidebarView(source string) bool {
+	return strings.HasPrefix(source, dashboardPrefix) || strings.HasPrefix(source, viewPrefix)
+}
+
+// resolveViews checks the saved views of the config file and expands the
+// teams they refer to, returning them by source ID and as sidebar entries
+func resolveViews(configs []config.ViewConfig, teams map[string][]string) (map[string]savedView, []ui.SidebarView, error) {
+	views := make(map[string]savedView, len(configs))
+	sidebar := make([]ui.SidebarView, 0, len(configs))
+	for i, v := range configs {
+		if v.Name == "" {
+			return nil, nil, fmt.Errorf("view %d has no name", i+1)
+		}
+		id := viewPrefix + v.Name
+		if _, ok := views[id]; ok {
+			return nil, nil, fmt.Errorf("view %q is defined twice", v.Name)
+		}
+		for _, glob := range v.Repos {
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, nil, fmt.Errorf("view %q: invalid repos pattern %q", v.Name, glob)
+			}
+		}
+		if v.Sort.Column != "" && !slices.Contains(ui.ColumnIDs(), v.Sort.Column) {
+			return nil, nil, fmt.Errorf("view %q: unknown sort column %q (expected %s)", v.Name, v.Sort.Column, strings.Join(ui.ColumnIDs(), ", "))
+		}
+
+		authors, err := expandTeams(v.Authors, teams)
+		if err != nil {
+			return nil, nil, fmt.Errorf("view %q: %w", v.Name, err)
+		}
+		reviewers, err := expandTeams(v.Reviewers, teams)
+		if err != nil {
+			return nil, nil, fmt.Errorf("view %q: %w", v.Name, err)
+		}
+
+		states := make([]string, len(v.States))
+		for i, state := range v.States {
+			states[i] = strings.ToUpper(state)
+		}
+
+		views[id] = savedView{
+			filter: api.ViewFilter{Repos: v.Repos, States: states, Authors: authors, Reviewers: reviewers, Query: v.Query},
+			sort:   ui.SortOrder{Column: v.Sort.Column, Desc: v.Sort.Desc},
+		}
+		sidebar = append(sidebar, ui.SidebarView{ID: id, Name: v.Name})
+	}
+	return views, sidebar, nil
+}
+
+// expandTeams replaces the @team entries of people with the team members
+func expandTeams(people []string, teams map[string][]string) ([]string, error) {
+	var expanded []string
+	for _, p := range people {
+		name, ok := strings.CutPrefix(p, "@")
+		if !ok {
+			expanded = append(expanded, p)
+			continue
+		}
+		members, ok := teams[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown team %q", p)
+		}
+		if len(members) == 0 {
+			return nil, fmt.Errorf("team %q has no members", p)
+		}
+		expanded = append(expanded, members...)
+	}
+	return expanded, nil
+}
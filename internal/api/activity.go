@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Activity is one entry of a PR's activity log. Exactly one field is set
+type Activity struct {
+	Comment        *Comment        `json:"comment,omitempty"`
+	Approval       *Approval       `json:"approval,omitempty"`
+	ChangesRequest *Approval       `json:"changes_request,omitempty"`
+	Update         *ActivityUpdate `json:"update,omitempty"`
+}
+
+// Approval is an approval or a change request
+type Approval struct {
+	Date time.Time  `json:"date"`
+	User AuthorInfo `json:"user"`
+}
+
+// ActivityUpdate is a snapshot of the PR taken whenever it changes
+type ActivityUpdate struct {
+	Date        time.Time    `json:"date"`
+	Author      AuthorInfo   `json:"author"`
+	State       string       `json:"state"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Reviewers   []AuthorInfo `json:"reviewers"`
+	Source      Endpoint     `json:"source"`
+	Destination Endpoint     `json:"destination"`
+}
+
+// ListPRActivity fetches the activity log of a pull request, newest first
+func (c *Client) ListPRActivity(repoSlug string, id int) ([]Activity, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/activity?pagelen=50", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	activity, err := getAll[Activity](c, url, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity for PR #%d: %w", id, err)
+	}
+
+	return activity, nil
+}
+
+// Event kinds of a PR timeline
+const (
+	EventComment        = "comment"
+	EventApproval       = "approval"
+	EventChangesRequest = "changes_request"
+	EventUpdate         = "update"
+)
+
+// TimelineEvent is something that happened to a PR
+type TimelineEvent struct {
+	Kind    string
+	Actor   AuthorInfo
+	Date    time.Time
+	Summary string
+}
+
+// Timeline turns an activity log into events, oldest first. Updates are
+// snapshots of the whole PR, so each one is compared with the previous
+// snapshot to describe what changed
+func Timeline(activity []Activity) []TimelineEvent {
+	var events []TimelineEvent
+	var previous *ActivityUpdate
+
+	// The log is newest first; walk it oldest first so updates can be compared
+	for i := len(activity) - 1; i >= 0; i-- {
+		a := activity[i]
+		switch {
+		case a.Comment != nil:
+			if a.Comment.Deleted {
+				continue
+			}
+			summary := "commented"
+			if a.Comment.Inline != nil {
+				summary = "commented on " + a.Comment.Inline.Path
+			}
+			if a.Comment.Parent != nil {
+				summary = "replied"
+			}
+			events = append(events, TimelineEvent{
+				Kind:    EventComment,
+				Actor:   a.Comment.User,
+				Date:    a.Comment.CreatedOn,
+				Summary: summary + ": " + firstLine(a.Comment.Content.Raw),
+			})
+
+		case a.Approval != nil:
+			events = append(events, TimelineEvent{Kind: EventApproval, Actor: a.Approval.User, Date: a.Approval.Date, Summary: "approved"})
+
+		case a.ChangesRequest != nil:
+			events = append(events, TimelineEvent{Kind: EventChangesRequest, Actor: a.ChangesRequest.User, Date: a.ChangesRequest.Date, Summary: "requested changes"})
+
+		case a.Update != nil:
+			for _, summary := range describeUpdate(previous, a.Update) {
+				events = append(events, TimelineEvent{Kind: EventUpdate, Actor: a.Update.Author, Date: a.Update.Date, Summary: summary})
+			}
+			previous = a.Update
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b TimelineEvent) int {
+		return a.Date.Compare(b.Date)
+	})
+	return events
+}
+
+// describeUpdate lists what changed between two snapshots of a PR
+func describeUpdate(previous, update *ActivityUpdate) []string {
+	if previous == nil {
+		return []string{"opened the pull request"}
+	}
+
+	var changes []string
+	if update.Source.Commit.Hash != previous.Source.Commit.Hash {
+		changes = append(changes, "pushed "+shortHash(update.Source.Commit.Hash))
+	}
+	if update.Title != previous.Title {
+		changes = append(changes, fmt.Sprintf("changed the title to %q", update.Title))
+	}
+	if update.Description != previous.Description {
+		changes = append(changes, "edited the description")
+	}
+	if update.Destination.Branch.Name != previous.Destination.Branch.Name {
+		changes = append(changes, "retargeted to "+update.Destination.Branch.Name)
+	}
+	if added, removed := reviewerChanges(previous.Reviewers, update.Reviewers); len(added)+len(removed) > 0 {
+		if len(added) > 0 {
+			changes = append(changes, "added reviewers "+strings.Join(added, ", "))
+		}
+		if len(removed) > 0 {
+			changes = append(changes, "removed reviewers "+strings.Join(removed, ", "))
+		}
+	}
+	if update.State != previous.State {
+		changes = append(changes, "marked the pull request "+strings.ToLower(update.State))
+	}
+	return changes
+}
+
+// reviewerChanges names the reviewers added and removed between two snapshots
+func reviewerChanges(before, after []AuthorInfo) (added, removed []string) {
+	has := func(list []AuthorInfo, user AuthorInfo) bool {
+		return slices.ContainsFunc(list, func(u AuthorInfo) bool { return u.UUID == user.UUID })
+	}
+
+	for _, user := range after {
+		if !has(before, user) {
+			added = append(added, user.FullName)
+		}
+	}
+	for _, user := range before {
+		if !has(after, user) {
+			removed = append(removed, user.FullName)
+		}
+	}
+	return added, removed
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i]) + " …"
+	}
+	return s
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// activityFixture is an activity log as Bitbucket returns it, newest first
+const activityFixture = `[
+	{"comment": {"id": 5, "content": {"raw": "gone"}, "user": {"display_name": "Bob"}, "created_on": "2026-01-01T16:00:00Z", "deleted": true}},
+	{"changes_request": {"date": "2026-01-01T15:00:00Z", "user": {"display_name": "Carol"}}},
+	{"comment": {"id": 4, "content": {"raw": "Done"}, "user": {"display_name": "Alice"}, "created_on": "2026-01-01T14:00:00Z", "parent": {"id": 3}}},
+	{"update": {
+		"date": "2026-01-01T13:00:00Z", "author": {"display_name": "Alice"}, "state": "OPEN", "title": "Fix login flow", "description": "Fixes it",
+		"reviewers": [{"display_name": "Carol", "uuid": "{c}"}],
+		"source": {"branch": {"name": "fix"}, "commit": {"hash": "bbbbbbbbbbbb"}}, "destination": {"branch": {"name": "main"}}
+	}},
+	{"comment": {"id": 3, "content": {"raw": "Why?\nMore details"}, "user": {"display_name": "Bob"}, "created_on": "2026-01-01T12:00:00Z", "inline": {"path": "auth.go", "to": 10}}},
+	{"approval": {"date": "2026-01-01T11:00:00Z", "user": {"display_name": "Bob"}}},
+	{"update": {
+		"date": "2026-01-01T10:00:00Z", "author": {"display_name": "Alice"}, "state": "OPEN", "title": "Fix login", "description": "Fixes it",
+		"reviewers": [{"display_name": "Bob", "uuid": "{b}"}],
+		"source": {"branch": {"name": "fix"}, "commit": {"hash": "aaaaaaaaaaaa"}}, "destination": {"branch": {"name": "main"}}
+	}}
+]`
+
+func TestTimeline(t *testing.T) {
+	var activity []Activity
+	if err := json.Unmarshal([]byte(activityFixture), &activity); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	want := []string{
+		"update Alice: opened the pull request",
+		"approval Bob: approved",
+		"comment Bob: commented on auth.go: Why? …",
+		"update Alice: pushed bbbbbbb",
+		`update Alice: changed the title to "Fix login flow"`,
+		"update Alice: added reviewers Carol",
+		"update Alice: removed reviewers Bob",
+		"comment Alice: replied: Done",
+		"changes_request Carol: requested changes",
+	}
+
+	var got []string
+	events := Timeline(activity)
+	for i, e := range events {
+		got = append(got, e.Kind+" "+e.Actor.FullName+": "+e.Summary)
+		if i > 0 && e.Date.Before(events[i-1].Date) {
+			t.Errorf("event %d is older than the one before it", i)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Timeline() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDescribeUpdate(t *testing.T) {
+	bob := AuthorInfo{FullName: "Bob", UUID: "{b}"}
+	carol := AuthorInfo{FullName: "Carol", UUID: "{c}"}
+	base := ActivityUpdate{
+		State:       "OPEN",
+		Title:       "Fix login",
+		Description: "Fixes it",
+		Reviewers:   []AuthorInfo{bob},
+		Source:      Endpoint{Commit: Commit{Hash: "aaaaaaaaaaaa"}},
+		Destination: Endpoint{Branch: Branch{Name: "main"}},
+	}
+
+	tests := []struct {
+		name   string
+		change func(u *ActivityUpdate)
+		want   []string
+	}{
+		{"nothing changed", func(u *ActivityUpdate) {}, nil},
+		{"push", func(u *ActivityUpdate) { u.Source.Commit.Hash = "abc" }, []string{"pushed abc"}},
+		{"title", func(u *ActivityUpdate) { u.Title = "Fix logout" }, []string{`changed the title to "Fix logout"`}},
+		{"description", func(u *ActivityUpdate) { u.Description = "" }, []string{"edited the description"}},
+		{"destination", func(u *ActivityUpdate) { u.Destination.Branch.Name = "develop" }, []string{"retargeted to develop"}},
+		{"reviewer added", func(u *ActivityUpdate) { u.Reviewers = []AuthorInfo{bob, carol} }, []string{"added reviewers Carol"}},
+		{"reviewer removed", func(u *ActivityUpdate) { u.Reviewers = nil }, []string{"removed reviewers Bob"}},
+		{"reviewer renamed", func(u *ActivityUpdate) { u.Reviewers = []AuthorInfo{{FullName: "Robert", UUID: "{b}"}} }, nil},
+		{"state", func(u *ActivityUpdate) { u.State = "MERGED" }, []string{"marked the pull request merged"}},
+		{
+			"several changes in order",
+			func(u *ActivityUpdate) { u.State = "DECLINED"; u.Title = "Nope"; u.Source.Commit.Hash = "bbbbbbbbbbbb" },
+			[]string{"pushed bbbbbbb", `changed the title to "Nope"`, "marked the pull request declined"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := base
+			update.Reviewers = slices.Clone(base.Reviewers)
+			tt.change(&update)
+
+			if got := describeUpdate(&base, &update); !slices.Equal(got, tt.want) {
+				t.Errorf("describeUpdate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := describeUpdate(nil, &base); !slices.Equal(got, []string{"opened the pull request"}) {
+		t.Errorf("describeUpdate(nil) = %q, want the opening", got)
+	}
+}
@@ -1,58 +1,98 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 type Client struct {
-	baseURL   string
-	email     string
-	apiToken  string
-	workspace string
-	repo      string
+	baseURL    string
+	email      string
+	apiToken   string
+	workspace  string
+	repo       string
+	httpClient *http.Client
+	// ctx cancels the requests of a client derived with WithContext
+	ctx context.Context
+	// identity is shared with the derived clients
+	identity *identity
+}
+
+// identity caches the authenticated user
+type identity struct {
+	mu   sync.Mutex
+	user *AuthorInfo
 }
 
 func NewClient(email, apiToken, workspace, repo string) *Client {
 	return &Client{
-		baseURL:   "https://api.bitbucket.org/2.0",
-		email:     email,
-		apiToken:  apiToken,
-		workspace: workspace,
-		repo:      repo,
+		baseURL:    "https://api.bitbucket.org/2.0",
+		email:      email,
+		apiToken:   apiToken,
+		workspace:  workspace,
+		repo:       repo,
+		httpClient: &http.Client{},
+		ctx:        context.Background(),
+		identity:   &identity{},
 	}
 }
 
-// FetchPRs fetches all pull requests from the repository
-// If repoSlug is empty, uses the default repo from client config
-func (c *Client) FetchPRs(repoSlug string) ([]PR, error) {
-	repo := c.repo
-	if repoSlug != "" {
-		repo = repoSlug
+// WithContext returns a client whose requests are cancelled along with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	derived := *c
+	derived.ctx = ctx
+	return &derived
+}
+
+// Workspace returns the workspace the client is scoped to
+func (c *Client) Workspace() string {
+	return c.workspace
+}
+
+// repoSlug falls back to the default repo from client config when slug is empty
+func (c *Client) repoSlug(slug string) string {
+	if slug != "" {
+		return slug
 	}
+	return c.repo
+}
 
-	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests", c.baseURL, c.workspace, repo)
+// StatusError is returned when the API responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// getRaw performs an authenticated GET request and returns the response body
+func (c *Client) getRaw(url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.SetBasicAuth(c.email, c.apiToken)
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", accept)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch PRs: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -60,50 +100,348 @@ func (c *Client) FetchPRs(repoSlug string) ([]PR, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var prList PRListResponse
-	if err := json.Unmarshal(body, &prList); err != nil {
-		return nil, fmt.Errorf("failed to parse PR list: %w", err)
+	return body, nil
+}
+
+// get performs an authenticated GET request and decodes the JSON response into out
+func (c *Client) get(url string, out any) error {
+	body, err := c.getRaw(url, "application/json")
+	if err != nil {
+		return err
 	}
 
-	return prList.Values, nil
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
 }
 
-// FetchRepositories fetches all repositories from the workspace with a specific role
-func (c *Client) FetchRepositories(role string) ([]Repository, error) {
-	url := fmt.Sprintf("%s/repositories/%s", c.baseURL, c.workspace)
-	if role != "" {
-		url = fmt.Sprintf("%s?role=%s", url, role)
+// send performs an authenticated request with a JSON body, decoding the JSON
+// response into out unless it is nil
+func (c *Client) send(method, url string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.ctx, method, url, reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.SetBasicAuth(c.email, c.apiToken)
 	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// page is a single page of a paginated API response
+type page[T any] struct {
+	Next   string `json:"next"`
+	Values []T    `json:"values"`
+}
+
+// getAll follows the next links of a paginated endpoint, collecting values
+// until there are no more pages or limit values were read (0 means no limit)
+func getAll[T any](c *Client, url string, limit int) ([]T, error) {
+	var values []T
+	for url != "" {
+		var p page[T]
+		if err := c.get(url, &p); err != nil {
+			return nil, err
+		}
+
+		values = append(values, p.Values...)
+		if limit > 0 && len(values) >= limit {
+			return values[:limit], nil
+		}
+		url = p.Next
+	}
+	return values, nil
+}
+
+// FetchPRs fetches all pull requests from the repository
+// If repoSlug is empty, uses the default repo from client config
+func (c *Client) FetchPRs(repoSlug string) ([]PR, error) {
+	query := url.Values{}
+	// Participants and reviewers are left out of the list representation by default
+	query.Set("fields", "+values.participants,+values.reviewers")
+
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests?%s", c.baseURL, c.workspace, c.repoSlug(repoSlug), query.Encode())
+
+	var prList PRListResponse
+	if err := c.get(url, &prList); err != nil {
+		return nil, fmt.Errorf("failed to fetch PRs: %w", err)
+	}
+
+	return prList.Values, nil
+}
+
+// PRListOptions filters the pull requests returned by ListPRs
+type PRListOptions struct {
+	// States filters by PR state (OPEN, MERGED, DECLINED, SUPERSEDED), the API defaults to OPEN
+	States []string
+	// Query is a BBQL filter such as reviewers.uuid="{...}"
+	Query string
+	// Limit caps the number of PRs fetched across pages, 0 means all
+	Limit int
+	// Sort orders the PRs by a field, descending with a leading "-" such as
+	// -updated_on; empty keeps the API order
+	Sort string
+}
+
+// encode builds the query string shared by the PR list endpoints
+func (o PRListOptions) encode() string {
+	query := url.Values{}
+	// Participants and reviewers are left out of the list representation by default
+	query.Set("fields", "+values.participants,+values.reviewers")
+	query.Set("pagelen", "50")
+	for _, state := range o.States {
+		query.Add("state", state)
+	}
+	if o.Query != "" {
+		query.Set("q", o.Query)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	return query.Encode()
+}
+
+// ListPRs fetches pull requests from the repository, following pagination
+// If repoSlug is empty, uses the default repo from client config
+func (c *Client) ListPRs(repoSlug string, opts PRListOptions) ([]PR, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests?%s", c.baseURL, c.workspace, c.repoSlug(repoSlug), opts.encode())
+
+	prs, err := getAll[PR](c, url, opts.Limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to fetch PRs: %w", err)
+	}
+
+	return prs, nil
+}
+
+// ListUserPRs fetches the pull requests authored by a user (UUID or account ID)
+// across all repositories, keeping those that target the client's workspace
+func (c *Client) ListUserPRs(user string, opts PRListOptions) ([]PR, error) {
+	url := fmt.Sprintf("%s/pullrequests/%s?%s", c.baseURL, url.PathEscape(user), opts.encode())
+
+	prs, err := getAll[PR](c, url, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PRs of %s: %w", user, err)
+	}
+
+	prefix := c.workspace + "/"
+	var inWorkspace []PR
+	for _, pr := range prs {
+		if strings.HasPrefix(pr.Destination.Repository.FullName, prefix) {
+			inWorkspace = append(inWorkspace, pr)
+		}
+	}
+
+	return inWorkspace, nil
+}
+
+// CurrentUser returns the authenticated user, fetching it once and caching it
+func (c *Client) CurrentUser() (*AuthorInfo, error) {
+	c.identity.mu.Lock()
+	defer c.identity.mu.Unlock()
+
+	if c.identity.user != nil {
+		return c.identity.user, nil
+	}
+
+	var user AuthorInfo
+	if err := c.get(c.baseURL+"/user", &user); err != nil {
+		return nil, fmt.Errorf("failed to fetch current user: %w", err)
+	}
+
+	c.identity.user = &user
+	return c.identity.user, nil
+}
+
+// FetchPR fetches a single pull request by ID
+func (c *Client) FetchPR(repoSlug string, id int) (*PR, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	var pr PR
+	if err := c.get(url, &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", id, err)
+	}
+
+	return &pr, nil
+}
+
+// FetchPRDiff fetches the unified diff of a pull request
+func (c *Client) FetchPRDiff(repoSlug string, id int) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diff", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	diff, err := c.getRaw(url, "text/plain")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch diff for PR #%d: %w", id, err)
+	}
+
+	return string(diff), nil
+}
+
+// ListPRCommits fetches the commits of a pull request, newest first, following pagination
+func (c *Client) ListPRCommits(repoSlug string, id int) ([]Commit, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/commits?pagelen=50", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	commits, err := getAll[Commit](c, url, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits for PR #%d: %w", id, err)
+	}
+
+	return commits, nil
+}
+
+// FetchDiff fetches the unified diff for a spec: a commit hash to diff it
+// against its parent, or two revisions as "new..old"
+func (c *Client) FetchDiff(repoSlug, spec string) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/diff/%s", c.baseURL, c.workspace, c.repoSlug(repoSlug), spec)
+
+	diff, err := c.getRaw(url, "text/plain")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch diff %s: %w", spec, err)
+	}
+
+	return string(diff), nil
+}
+
+// FetchPRStatuses fetches the build statuses reported for a pull request
+func (c *Client) FetchPRStatuses(repoSlug string, id int) ([]BuildStatus, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/statuses", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	var statusList BuildStatusListResponse
+	if err := c.get(url, &statusList); err != nil {
+		return nil, fmt.Errorf("failed to fetch statuses for PR #%d: %w", id, err)
 	}
 
-	var repoList RepositoryListResponse
-	if err := json.Unmarshal(body, &repoList); err != nil {
-		return nil, fmt.Errorf("failed to parse repository list: %w", err)
+	return statusList.Values, nil
+}
+
+// ListPRComments fetches every comment of a pull request, following pagination
+func (c *Client) ListPRComments(repoSlug string, id int) ([]Comment, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments?pagelen=100", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	comments, err := getAll[Comment](c, url, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments for PR #%d: %w", id, err)
+	}
+
+	return comments, nil
+}
+
+// CreatePRComment adds a general comment to a pull request
+func (c *Client) CreatePRComment(repoSlug string, id int, content string) (*Comment, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	var comment Comment
+	if err := c.send("POST", url, Comment{Content: Content{Raw: content}}, &comment); err != nil {
+		return nil, fmt.Errorf("failed to comment on PR #%d: %w", id, err)
+	}
+
+	return &comment, nil
+}
+
+// DeclinePR declines a pull request. The API takes no reason, so a reason is
+// left as a comment first
+func (c *Client) DeclinePR(repoSlug string, id int, reason string) error {
+	if reason != "" {
+		if _, err := c.CreatePRComment(repoSlug, id, "Declined: "+reason); err != nil {
+			return err
+		}
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/decline", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+	if err := c.send("POST", url, nil, nil); err != nil {
+		return fmt.Errorf("failed to decline PR #%d: %w", id, err)
+	}
+
+	return nil
+}
+
+// RepoRoles are the role scopes repositories can be listed with, from the
+// weakest to the strongest
+var RepoRoles = []string{"member", "contributor", "admin", "owner"}
+
+// RepoListOptions filters the repositories returned by ListRepositories
+type RepoListOptions struct {
+	// Role is the minimum role of the user: member, contributor, admin or owner
+	Role string
+	// Project limits the list to the project with this key
+	Project string
+	// Limit caps the number of repositories fetched across pages, 0 means all
+	Limit int
+}
+
+// ListRepositories fetches the workspace repositories, following pagination
+func (c *Client) ListRepositories(opts RepoListOptions) ([]Repository, error) {
+	query := url.Values{}
+	query.Set("pagelen", "100")
+	if opts.Role != "" {
+		query.Set("role", opts.Role)
+	}
+	if opts.Project != "" {
+		query.Set("q", fmt.Sprintf(`project.key=%s`, bbqlString(opts.Project)))
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s?%s", c.baseURL, c.workspace, query.Encode())
+
+	repos, err := getAll[Repository](c, url, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+	}
+
+	return repos, nil
+}
+
+// bbqlString quotes a value for a BBQL filter
+func bbqlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// CountOpenPRs returns the number of open pull requests of a repository
+func (c *Client) CountOpenPRs(repoSlug string) (int, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests?state=OPEN&pagelen=1&fields=size", c.baseURL, c.workspace, c.repoSlug(repoSlug))
+
+	var resp struct {
+		Size int `json:"size"`
+	}
+	if err := c.get(url, &resp); err != nil {
+		return 0, fmt.Errorf("failed to count PRs of %s: %w", repoSlug, err)
 	}
 
-	return repoList.Values, nil
+	return resp.Size, nil
 }
@@ -0,0 +1,80 @@
+package api
+
+import "fmt"
+
+// Dashboard is a cross-repository view of the pull requests relevant to the
+// authenticated user
+type Dashboard string
+
+const (
+	// DashboardAuthored lists open PRs the user created
+	DashboardAuthored Dashboard = "authored"
+	// DashboardReviewing lists open PRs where the user is a reviewer and has not approved yet
+	DashboardReviewing Dashboard = "reviewing"
+	// DashboardParticipated lists open PRs the user reviewed or commented on
+	DashboardParticipated Dashboard = "participated"
+)
+
+// Dashboards lists every dashboard in display order
+var Dashboards = []Dashboard{DashboardAuthored, DashboardReviewing, DashboardParticipated}
+
+// Title is the human-readable name of the dashboard
+func (d Dashboard) Title() string {
+	switch d {
+	case DashboardAuthored:
+		return "My PRs"
+	case DashboardReviewing:
+		return "Review requested"
+	case DashboardParticipated:
+		return "Participated"
+	default:
+		return string(d)
+	}
+}
+
+// WorkspaceWide reports whether a dashboard is fetched with a single query
+// across the workspace rather than one per repository
+func (d Dashboard) WorkspaceWide() bool {
+	return d == DashboardAuthored
+}
+
+// DashboardQuery returns how the PRs of a dashboard are fetched from each repository,
+// to run with FetchAcross. The query of a workspace-wide dashboard ignores the
+// repository and is run once
+func (c *Client) DashboardQuery(d Dashboard) (RepoQuery, error) {
+	user, err := c.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	switch d {
+	case DashboardAuthored:
+		return func(c *Client, _ string) ([]PR, error) {
+			return c.ListUserPRs(user.UUID, PRListOptions{States: []string{"OPEN"}})
+		}, nil
+
+	case DashboardReviewing:
+		return func(c *Client, slug string) ([]PR, error) {
+			prs, err := c.ListPRs(slug, PRListOptions{States: []string{"OPEN"}, Query: fmt.Sprintf(`reviewers.uuid="%s"`, user.UUID)})
+			return filterPRs(prs, func(pr PR) bool { return !pr.ApprovedBy(user.UUID) }), err
+		}, nil
+
+	case DashboardParticipated:
+		return func(c *Client, slug string) ([]PR, error) {
+			return c.ListPRs(slug, PRListOptions{States: []string{"OPEN"}, Query: fmt.Sprintf(`participants.uuid="%s"`, user.UUID)})
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown dashboard %q", d)
+	}
+}
+
+func filterPRs(prs []PR, keep func(pr PR) bool) []PR {
+	var kept []PR
+	for _, pr := range prs {
+		if keep(pr) {
+			kept = append(kept, pr)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// PRUpdate is the editable part of a pull request, sent in full on update
+type PRUpdate struct {
+	Title       string
+	Description string
+	// Reviewers holds the UUIDs of the requested reviewers
+	Reviewers   []string
+	Destination string
+}
+
+// prUpdateRequest is the body of a pull request update
+type prUpdateRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Reviewers   []Reviewer `json:"reviewers"`
+	Destination branchRef  `json:"destination"`
+}
+
+// branchRef is the side of a pull request in requests, which only name the branch
+type branchRef struct {
+	Branch Branch `json:"branch"`
+}
+
+// ConflictError is returned when a pull request changed since it was loaded
+type ConflictError struct {
+	Current *PR
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("PR #%d was updated on %s since it was loaded", e.Current.ID, e.Current.UpdatedOn.Local().Format(time.DateTime))
+}
+
+// UpdatePR changes a pull request. Unless loadedAt is zero, the update is
+// refused with a ConflictError when the pull request was updated after it
+func (c *Client) UpdatePR(repoSlug string, id int, update PRUpdate, loadedAt time.Time) (*PR, error) {
+	if !loadedAt.IsZero() {
+		current, err := c.FetchPR(repoSlug, id)
+		if err != nil {
+			return nil, err
+		}
+		if !current.UpdatedOn.Equal(loadedAt) {
+			return nil, &ConflictError{Current: current}
+		}
+	}
+
+	body := prUpdateRequest{
+		Title:       update.Title,
+		Description: update.Description,
+		Reviewers:   make([]Reviewer, len(update.Reviewers)),
+		Destination: branchRef{Branch: Branch{Name: update.Destination}},
+	}
+	for i, uuid := range update.Reviewers {
+		body.Reviewers[i] = Reviewer{UUID: uuid}
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	var pr PR
+	if err := c.send("PUT", url, body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to update PR #%d: %w", id, err)
+	}
+
+	return &pr, nil
+}
+
+// PRCreate is a new pull request
+type PRCreate struct {
+	Title       string
+	Description string
+	Source      string
+	Destination string
+	// Reviewers holds the UUIDs of the requested reviewers
+	Reviewers         []string
+	CloseSourceBranch bool
+}
+
+// prCreateRequest is the body of a pull request creation
+type prCreateRequest struct {
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	Source            branchRef  `json:"source"`
+	Destination       branchRef  `json:"destination"`
+	Reviewers         []Reviewer `json:"reviewers"`
+	CloseSourceBranch bool       `json:"close_source_branch"`
+}
+
+// CreatePR opens a pull request
+func (c *Client) CreatePR(repoSlug string, create PRCreate) (*PR, error) {
+	body := prCreateRequest{
+		Title:             create.Title,
+		Description:       create.Description,
+		Source:            branchRef{Branch: Branch{Name: create.Source}},
+		Destination:       branchRef{Branch: Branch{Name: create.Destination}},
+		Reviewers:         make([]Reviewer, len(create.Reviewers)),
+		CloseSourceBranch: create.CloseSourceBranch,
+	}
+	for i, uuid := range create.Reviewers {
+		body.Reviewers[i] = Reviewer{UUID: uuid}
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests", c.baseURL, c.workspace, c.repoSlug(repoSlug))
+
+	var pr PR
+	if err := c.send("POST", url, body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	return &pr, nil
+}
+
+// maxMembers and maxBranches bound the suggestions loaded for the edit form
+const (
+	maxMembers  = 1000
+	maxBranches = 1000
+)
+
+// ListWorkspaceMembers fetches the members of the workspace, the candidate reviewers
+func (c *Client) ListWorkspaceMembers() ([]AuthorInfo, error) {
+	type membership struct {
+		User AuthorInfo `json:"user"`
+	}
+
+	url := fmt.Sprintf("%s/workspaces/%s/members?pagelen=100", c.baseURL, c.workspace)
+
+	memberships, err := getAll[membership](c, url, maxMembers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch workspace members: %w", err)
+	}
+
+	members := make([]AuthorInfo, len(memberships))
+	for i, m := range memberships {
+		members[i] = m.User
+	}
+	return members, nil
+}
+
+// ListBranches fetches the branches of a repository
+func (c *Client) ListBranches(repoSlug string) ([]Branch, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/refs/branches?pagelen=100&fields=values.name,next", c.baseURL, c.workspace, c.repoSlug(repoSlug))
+
+	branches, err := getAll[Branch](c, url, maxBranches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branches: %w", err)
+	}
+
+	return branches, nil
+}
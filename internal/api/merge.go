@@ -0,0 +1,334 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+// BranchRestriction is a rule on the branches matching a pattern
+type BranchRestriction struct {
+	Kind            string       `json:"kind"`
+	BranchMatchKind string       `json:"branch_match_kind"`
+	Pattern         string       `json:"pattern"`
+	Value           *int         `json:"value"`
+	Users           []AuthorInfo `json:"users"`
+}
+
+// Applies reports whether the restriction covers a branch. Restrictions on
+// branch types of the branching model are not resolved and never apply
+func (r BranchRestriction) Applies(branch string) bool {
+	if r.BranchMatchKind != "glob" {
+		return false
+	}
+	matched, err := path.Match(r.Pattern, branch)
+	return err == nil && matched
+}
+
+// ListBranchRestrictions fetches the branch restrictions of a repository,
+// which requires admin access to it
+func (c *Client) ListBranchRestrictions(repoSlug string) ([]BranchRestriction, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/branch-restrictions?pagelen=100", c.baseURL, c.workspace, c.repoSlug(repoSlug))
+
+	restrictions, err := getAll[BranchRestriction](c, url, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branch restrictions: %w", err)
+	}
+
+	return restrictions, nil
+}
+
+// DiffStat is the change summary of one file of a diff
+type DiffStat struct {
+	Status       string `json:"status"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Old          *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+// Path returns the path of the file after the change, or before it when removed
+func (d DiffStat) Path() string {
+	if d.New != nil {
+		return d.New.Path
+	}
+	if d.Old != nil {
+		return d.Old.Path
+	}
+	return ""
+}
+
+// Conflicted reports whether the file cannot be merged cleanly
+func (d DiffStat) Conflicted() bool {
+	switch d.Status {
+	case "merge conflict", "local deleted", "remote deleted":
+		return true
+	}
+	return false
+}
+
+// ListPRDiffStat fetches the per-file change summary of a pull request
+func (c *Client) ListPRDiffStat(repoSlug string, id int) ([]DiffStat, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diffstat?pagelen=500", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	stats, err := getAll[DiffStat](c, url, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch diffstat for PR #%d: %w", id, err)
+	}
+
+	return stats, nil
+}
+
+// MergeOptions are sent with a merge, empty values use the repository defaults
+type MergeOptions struct {
+	// Strategy is merge_commit, squash or fast_forward
+	Strategy          string `json:"merge_strategy,omitempty"`
+	Message           string `json:"message,omitempty"`
+	CloseSourceBranch bool   `json:"close_source_branch,omitempty"`
+}
+
+// MergePR merges a pull request
+func (c *Client) MergePR(repoSlug string, id int, opts MergeOptions) error {
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/merge", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+
+	if err := c.send("POST", url, opts, nil); err != nil {
+		return fmt.Errorf("failed to merge PR #%d: %w", id, err)
+	}
+
+	return nil
+}
+
+// CheckStatus is the outcome of a merge check
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckFailed  CheckStatus = "failed"
+	CheckUnknown CheckStatus = "unknown"
+	// CheckPending marks a check waiting for builds still running
+	CheckPending CheckStatus = "pending"
+)
+
+// MergeCheck is one condition for merging a pull request
+type MergeCheck struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// MergeReadiness evaluates whether a pull request can be merged: approvals,
+// change requests, builds, open tasks, conflicts and branch restrictions.
+// Checks that cannot be evaluated are reported as unknown and builds still
+// running as pending, rather than failing
+func (c *Client) MergeReadiness(repoSlug string, id int) ([]MergeCheck, error) {
+	pr, err := c.FetchPR(repoSlug, id)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, statusesErr := c.FetchPRStatuses(repoSlug, id)
+	stats, statsErr := c.ListPRDiffStat(repoSlug, id)
+
+	// Only repository admins may read the restrictions
+	restrictions, err := c.ListBranchRestrictions(repoSlug)
+	var statusErr *StatusError
+	restrictionsKnown := err == nil
+	if err != nil && !(errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden) {
+		return nil, err
+	}
+
+	var applicable []BranchRestriction
+	for _, r := range restrictions {
+		if r.Applies(pr.Destination.Branch.Name) {
+			applicable = append(applicable, r)
+		}
+	}
+
+	builds := buildsCheck(statuses, applicable)
+	if statusesErr != nil {
+		builds = unknownCheck(builds.Name, statusesErr)
+	}
+	conflicts := conflictsCheck(stats)
+	if statsErr != nil {
+		conflicts = unknownCheck(conflicts.Name, statsErr)
+	}
+
+	checks := []MergeCheck{
+		approvalsCheck(*pr, applicable, restrictionsKnown),
+		changesRequestedCheck(*pr),
+		builds,
+		tasksCheck(*pr),
+		conflicts,
+	}
+
+	user, err := c.CurrentUser()
+	if err != nil {
+		user = nil
+	}
+	checks = append(checks, restrictionsCheck(applicable, restrictionsKnown, user))
+
+	return checks, nil
+}
+
+// requiredValue returns the value of the first applicable restriction of a kind
+func requiredValue(restrictions []BranchRestriction, kind string) (int, bool) {
+	for _, r := range restrictions {
+		if r.Kind == kind {
+			if r.Value == nil {
+				return 1, true
+			}
+			return *r.Value, true
+		}
+	}
+	return 0, false
+}
+
+func approvalsCheck(pr PR, restrictions []BranchRestriction, restrictionsKnown bool) MergeCheck {
+	approvals := 0
+	for _, p := range pr.Participants {
+		if p.Approved {
+			approvals++
+		}
+	}
+
+	check := MergeCheck{Name: "Approvals"}
+	required, ok := requiredValue(restrictions, "require_approvals_to_merge")
+	switch {
+	case ok:
+		check.Detail = fmt.Sprintf("%d of %d required", approvals, required)
+		check.Status = passIf(approvals >= required)
+	case !restrictionsKnown:
+		check.Detail = fmt.Sprintf("%d, requirement unknown", approvals)
+		check.Status = CheckUnknown
+		if approvals > 0 {
+			check.Status = CheckPassed
+		}
+	default:
+		check.Detail = fmt.Sprintf("%d, none required", approvals)
+		check.Status = CheckPassed
+	}
+	return check
+}
+
+func changesRequestedCheck(pr PR) MergeCheck {
+	var requesters []string
+	for _, p := range pr.Participants {
+		if p.State == "changes_requested" {
+			requesters = append(requesters, p.User.FullName)
+		}
+	}
+
+	if len(requesters) == 0 {
+		return MergeCheck{Name: "Changes requested", Status: CheckPassed, Detail: "none"}
+	}
+	return MergeCheck{Name: "Changes requested", Status: CheckFailed, Detail: "by " + strings.Join(requesters, ", ")}
+}
+
+func buildsCheck(statuses []BuildStatus, restrictions []BranchRestriction) MergeCheck {
+	passing := 0
+	var failing, running []string
+	for _, s := range statuses {
+		switch s.State {
+		case "SUCCESSFUL":
+			passing++
+		case "INPROGRESS":
+			running = append(running, s.Name)
+		default:
+			failing = append(failing, fmt.Sprintf("%s (%s)", s.Name, strings.ToLower(s.State)))
+		}
+	}
+
+	check := MergeCheck{Name: "Builds"}
+	switch {
+	case len(failing) > 0:
+		check.Status = CheckFailed
+		check.Detail = strings.Join(failing, ", ")
+	case len(running) > 0:
+		check.Status = CheckPending
+		check.Detail = "running: " + strings.Join(running, ", ")
+	case len(statuses) == 0:
+		check.Status = CheckPassed
+		check.Detail = "no builds"
+	default:
+		check.Status = CheckPassed
+		check.Detail = fmt.Sprintf("%d passing", passing)
+	}
+
+	if required, ok := requiredValue(restrictions, "require_passing_builds_to_merge"); ok && passing < required {
+		check.Detail = fmt.Sprintf("%d of %d required passing", passing, required)
+		// The builds still running may yet make up the difference
+		if check.Status != CheckFailed && passing+len(running) >= required {
+			check.Status = CheckPending
+			check.Detail += fmt.Sprintf(", %d running", len(running))
+		} else {
+			check.Status = CheckFailed
+		}
+	}
+	return check
+}
+
+func tasksCheck(pr PR) MergeCheck {
+	if pr.TaskCount == 0 {
+		return MergeCheck{Name: "Open tasks", Status: CheckPassed, Detail: "none"}
+	}
+	return MergeCheck{Name: "Open tasks", Status: CheckFailed, Detail: fmt.Sprintf("%d open", pr.TaskCount)}
+}
+
+func conflictsCheck(stats []DiffStat) MergeCheck {
+	var conflicted []string
+	for _, s := range stats {
+		if s.Conflicted() {
+			conflicted = append(conflicted, s.Path())
+		}
+	}
+
+	if len(conflicted) == 0 {
+		return MergeCheck{Name: "Conflicts", Status: CheckPassed, Detail: "none"}
+	}
+	return MergeCheck{Name: "Conflicts", Status: CheckFailed, Detail: strings.Join(conflicted, ", ")}
+}
+
+func restrictionsCheck(restrictions []BranchRestriction, restrictionsKnown bool, user *AuthorInfo) MergeCheck {
+	check := MergeCheck{Name: "Branch restrictions"}
+	if !restrictionsKnown {
+		check.Status = CheckUnknown
+		check.Detail = "only repository admins can read them"
+		return check
+	}
+
+	for _, r := range restrictions {
+		if r.Kind != "restrict_merges" {
+			continue
+		}
+		if user != nil && slices.ContainsFunc(r.Users, func(u AuthorInfo) bool { return u.UUID == user.UUID }) {
+			continue
+		}
+		// Merges may still be allowed through a group, which is not resolved here
+		check.Status = CheckUnknown
+		check.Detail = "merging is restricted to some users and groups"
+		return check
+	}
+
+	check.Status = CheckPassed
+	check.Detail = "merging allowed"
+	return check
+}
+
+// unknownCheck is a check that could not be evaluated because of err
+func unknownCheck(name string, err error) MergeCheck {
+	return MergeCheck{Name: name, Status: CheckUnknown, Detail: err.Error()}
+}
+
+func passIf(ok bool) CheckStatus {
+	if ok {
+		return CheckPassed
+	}
+	return CheckFailed
+}
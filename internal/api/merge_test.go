@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestApprovalsCheck(t *testing.T) {
+	approved := Participant{Approved: true}
+	requireTwo := []BranchRestriction{{Kind: "require_approvals_to_merge", Value: intPtr(2)}}
+
+	tests := []struct {
+		name              string
+		participants      []Participant
+		restrictions      []BranchRestriction
+		restrictionsKnown bool
+		want              MergeCheck
+	}{
+		{
+			"unknown requirement without approvals", nil, nil, false,
+			MergeCheck{Name: "Approvals", Status: CheckUnknown, Detail: "0, requirement unknown"},
+		},
+		{
+			"unknown requirement with approvals", []Participant{approved}, nil, false,
+			MergeCheck{Name: "Approvals", Status: CheckPassed, Detail: "1, requirement unknown"},
+		},
+		{
+			"none required", nil, nil, true,
+			MergeCheck{Name: "Approvals", Status: CheckPassed, Detail: "0, none required"},
+		},
+		{
+			"too few approvals", []Participant{approved, {}}, requireTwo, true,
+			MergeCheck{Name: "Approvals", Status: CheckFailed, Detail: "1 of 2 required"},
+		},
+		{
+			"enough approvals", []Participant{approved, approved}, requireTwo, true,
+			MergeCheck{Name: "Approvals", Status: CheckPassed, Detail: "2 of 2 required"},
+		},
+		{
+			"requirement without a value", nil, []BranchRestriction{{Kind: "require_approvals_to_merge"}}, true,
+			MergeCheck{Name: "Approvals", Status: CheckFailed, Detail: "0 of 1 required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := approvalsCheck(PR{Participants: tt.participants}, tt.restrictions, tt.restrictionsKnown)
+			if got != tt.want {
+				t.Errorf("approvalsCheck() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildsCheck(t *testing.T) {
+	passed := BuildStatus{Name: "unit", State: "SUCCESSFUL"}
+	running := BuildStatus{Name: "e2e", State: "INPROGRESS"}
+	failed := BuildStatus{Name: "lint", State: "FAILED"}
+	stopped := BuildStatus{Name: "deploy", State: "STOPPED"}
+	requireTwo := []BranchRestriction{{Kind: "require_passing_builds_to_merge", Value: intPtr(2)}}
+
+	tests := []struct {
+		name         string
+		statuses     []BuildStatus
+		restrictions []BranchRestriction
+		wantStatus   CheckStatus
+		wantDetail   string
+	}{
+		{"no builds", nil, nil, CheckPassed, "no builds"},
+		{"all passed", []BuildStatus{passed, passed}, nil, CheckPassed, "2 passing"},
+		{"running is pending", []BuildStatus{passed, running}, nil, CheckPending, "running: e2e"},
+		{"failed fails", []BuildStatus{passed, failed}, nil, CheckFailed, "lint (failed)"},
+		{"stopped fails", []BuildStatus{stopped}, nil, CheckFailed, "deploy (stopped)"},
+		{"failed wins over running", []BuildStatus{running, failed}, nil, CheckFailed, "lint (failed)"},
+		{"required passing met", []BuildStatus{passed, passed}, requireTwo, CheckPassed, "2 passing"},
+		{"required passing may still be met", []BuildStatus{passed, running}, requireTwo, CheckPending, "1 of 2 required passing, 1 running"},
+		{"required passing cannot be met", []BuildStatus{passed}, requireTwo, CheckFailed, "1 of 2 required passing"},
+		{"required passing with a failure", []BuildStatus{running, failed}, requireTwo, CheckFailed, "0 of 2 required passing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildsCheck(tt.statuses, tt.restrictions)
+			if got.Status != tt.wantStatus || got.Detail != tt.wantDetail {
+				t.Errorf("buildsCheck() = %s %q, want %s %q", got.Status, got.Detail, tt.wantStatus, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestMergeReadinessUnknownChecks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repositories/ws/app/pullrequests/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":1,"destination":{"branch":{"name":"main"}}}`))
+	})
+	mux.HandleFunc("GET /repositories/ws/app/pullrequests/1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /repositories/ws/app/pullrequests/1/diffstat", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "too large", http.StatusBadGateway)
+	})
+	mux.HandleFunc("GET /repositories/ws/app/branch-restrictions", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "admins only", http.StatusForbidden)
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"uuid":"{me}"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient("", "", "ws", "app")
+	client.baseURL = server.URL
+
+	checks, err := client.MergeReadiness("", 1)
+	if err != nil {
+		t.Fatalf("MergeReadiness failed: %v", err)
+	}
+
+	want := map[string]CheckStatus{
+		"Approvals":           CheckUnknown,
+		"Changes requested":   CheckPassed,
+		"Builds":              CheckUnknown,
+		"Open tasks":          CheckPassed,
+		"Conflicts":           CheckUnknown,
+		"Branch restrictions": CheckUnknown,
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(checks), len(want), checks)
+	}
+	for _, check := range checks {
+		if check.Status != want[check.Name] {
+			t.Errorf("%s = %s, want %s", check.Name, check.Status, want[check.Name])
+		}
+	}
+	for _, check := range checks {
+		if check.Name == "Builds" && !strings.Contains(check.Detail, "500") {
+			t.Errorf("Builds detail = %q, want the error", check.Detail)
+		}
+	}
+}
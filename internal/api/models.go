@@ -1,24 +1,53 @@
 package api
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type PR struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Author      AuthorInfo `json:"author"`
-	State       string     `json:"state"`
-	CreatedOn   time.Time  `json:"created_on"`
-	UpdatedOn   time.Time  `json:"updated_on"`
-	Links       Links      `json:"links"`
-	Reviewers   []Reviewer `json:"reviewers"`
-	Source      Branch     `json:"source"`
-	Destination Branch     `json:"destination"`
+	ID           int           `json:"id"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	Author       AuthorInfo    `json:"author"`
+	State        string        `json:"state"`
+	CreatedOn    time.Time     `json:"created_on"`
+	UpdatedOn    time.Time     `json:"updated_on"`
+	Links        Links         `json:"links"`
+	Reviewers    []Reviewer    `json:"reviewers"`
+	Source       Endpoint      `json:"source"`
+	Destination  Endpoint      `json:"destination"`
+	Participants []Participant `json:"participants"`
+	CommentCount int           `json:"comment_count"`
+	TaskCount    int           `json:"task_count"`
+}
+
+// ApprovedBy reports whether the participant with the given UUID approved the PR
+func (pr PR) ApprovedBy(uuid string) bool {
+	for _, participant := range pr.Participants {
+		if participant.User.UUID == uuid {
+			return participant.Approved
+		}
+	}
+	return false
+}
+
+// HasReviewer reports whether the user with the given UUID is a requested reviewer
+func (pr PR) HasReviewer(uuid string) bool {
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.UUID == uuid {
+			return true
+		}
+	}
+	return false
 }
 
 type AuthorInfo struct {
-	Username string `json:"username"`
-	FullName string `json:"display_name"`
+	Username  string `json:"username,omitempty"`
+	FullName  string `json:"display_name"`
+	Nickname  string `json:"nickname,omitempty"`
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
 }
 
 type Links struct {
@@ -31,15 +60,57 @@ type HTML struct {
 }
 
 type Reviewer struct {
-	Username string `json:"username"`
-	FullName string `json:"display_name"`
+	Username  string `json:"username,omitempty"`
+	FullName  string `json:"display_name"`
+	Nickname  string `json:"nickname,omitempty"`
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
 }
 
-type Branch struct {
-	Name       string `json:"name"`
+type Participant struct {
+	User     AuthorInfo `json:"user"`
+	Role     string     `json:"role"`
+	Approved bool       `json:"approved"`
+	State    string     `json:"state"`
+}
+
+// Endpoint is the source or destination side of a pull request
+type Endpoint struct {
+	Branch     Branch `json:"branch"`
+	Commit     Commit `json:"commit"`
 	Repository Repo   `json:"repository"`
 }
 
+type Branch struct {
+	Name string `json:"name"`
+}
+
+// Commit is a commit; endpoints of a PR only carry the hash
+type Commit struct {
+	Hash    string       `json:"hash"`
+	Message string       `json:"message,omitempty"`
+	Date    time.Time    `json:"date,omitzero"`
+	Author  CommitAuthor `json:"author,omitzero"`
+}
+
+// CommitAuthor is the git author of a commit, with the matching Bitbucket
+// account when there is one
+type CommitAuthor struct {
+	Raw  string      `json:"raw"`
+	User *AuthorInfo `json:"user,omitempty"`
+}
+
+// Name returns the account name of the author, or the git author without the email
+func (a CommitAuthor) Name() string {
+	if a.User != nil && a.User.FullName != "" {
+		return a.User.FullName
+	}
+	if i := strings.Index(a.Raw, " <"); i >= 0 {
+		return a.Raw[:i]
+	}
+	return a.Raw
+}
+
 type Repo struct {
 	FullName string `json:"full_name"`
 }
@@ -54,16 +125,85 @@ type PRListResponse struct {
 }
 
 type Repository struct {
-	Slug  string `json:"slug"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	FullName  string    `json:"full_name"`
+	Links     Links     `json:"links"`
+	Project   Project   `json:"project"`
+	Language  string    `json:"language"`
+	IsPrivate bool      `json:"is_private"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+// Project is the Bitbucket project a repository belongs to
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Comment is a PR comment, general or inline on the diff
+type Comment struct {
+	ID        int          `json:"id"`
+	Content   Content      `json:"content"`
+	User      AuthorInfo   `json:"user"`
+	CreatedOn time.Time    `json:"created_on"`
+	UpdatedOn time.Time    `json:"updated_on"`
+	Deleted   bool         `json:"deleted"`
+	Inline    *Inline      `json:"inline,omitempty"`
+	Parent    *CommentLink `json:"parent,omitempty"`
+	Links     Links        `json:"links"`
+}
+
+type Content struct {
+	Raw string `json:"raw"`
+}
+
+// Inline locates a comment on the diff, From is set on removed lines and To on added ones
+type Inline struct {
+	Path string `json:"path"`
+	From *int   `json:"from"`
+	To   *int   `json:"to"`
+}
+
+type CommentLink struct {
+	ID int `json:"id"`
+}
+
+type BuildStatus struct {
+	Key   string `json:"key"`
 	Name  string `json:"name"`
-	Links Links  `json:"links"`
+	State string `json:"state"`
+	URL   string `json:"url"`
 }
 
-type RepositoryListResponse struct {
-	Pagelen  int          `json:"pagelen"`
-	Page     int          `json:"page"`
-	Size     int          `json:"size"`
-	Next     string       `json:"next"`
-	Previous string       `json:"previous"`
-	Values   []Repository `json:"values"`
+type BuildStatusListResponse struct {
+	Pagelen int           `json:"pagelen"`
+	Page    int           `json:"page"`
+	Size    int           `json:"size"`
+	Next    string        `json:"next"`
+	Values  []BuildStatus `json:"values"`
+}
+
+// SummarizeStatuses reduces the build statuses of a PR to a single state:
+// FAILED if any build failed, INPROGRESS if any is running, STOPPED if any was
+// stopped, SUCCESSFUL when all passed and empty when there are no builds
+func SummarizeStatuses(statuses []BuildStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+
+	summary := "SUCCESSFUL"
+	for _, status := range statuses {
+		switch status.State {
+		case "FAILED":
+			return "FAILED"
+		case "INPROGRESS":
+			summary = "INPROGRESS"
+		case "STOPPED":
+			if summary != "INPROGRESS" {
+				summary = "STOPPED"
+			}
+		}
+	}
+	return summary
 }
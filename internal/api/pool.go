@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// DefaultWorkers is the number of requests FetchEach runs at once
+const DefaultWorkers = 8
+
+// Result is the outcome of fetching one item with FetchEach
+type Result[K, V any] struct {
+	Item  K
+	Value V
+	Err   error
+}
+
+// FetchEach runs fetch for each item, at most workers at a time. Each result
+// is sent as soon as its item is done, and a failing item does not stop the
+// others. The channel is closed once every item is done, or early when ctx is
+// cancelled, which also aborts the requests in flight
+func FetchEach[K, V any](ctx context.Context, c *Client, items []K, workers int, fetch func(c *Client, item K) (V, error)) <-chan Result[K, V] {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	jobs := make(chan K)
+	// Buffered so workers never block on a reader that has gone away
+	results := make(chan Result[K, V], len(items))
+	client := c.WithContext(ctx)
+
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				value, err := fetch(client, item)
+				if ctx.Err() != nil {
+					return
+				}
+				results <- Result[K, V]{Item: item, Value: value, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, item := range items {
+			select {
+			case jobs <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// RepoQuery fetches the pull requests of one repository for an aggregated view
+type RepoQuery func(c *Client, repoSlug string) ([]PR, error)
+
+// RepoResult is the outcome of querying one repository
+type RepoResult = Result[string, []PR]
+
+// FetchAcross runs query against each repository with FetchEach
+func (c *Client) FetchAcross(ctx context.Context, repoSlugs []string, workers int, query RepoQuery) <-chan RepoResult {
+	return FetchEach(ctx, c, repoSlugs, workers, query)
+}
+
+// SortByUpdated orders pull requests most recently updated first
+func SortByUpdated(prs []PR) {
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prs[i].UpdatedOn.After(prs[j].UpdatedOn)
+	})
+}
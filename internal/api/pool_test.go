@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// collect drains results, failing the test if the channel is not closed in time
+func collect[K, V any](t *testing.T, results <-chan Result[K, V]) []Result[K, V] {
+	t.Helper()
+
+	var all []Result[K, V]
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return all
+			}
+			all = append(all, result)
+		case <-timeout:
+			t.Fatalf("results not closed after %d items", len(all))
+			return nil
+		}
+	}
+}
+
+func TestFetchEach(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	results := collect(t, FetchEach(context.Background(), NewClient("", "", "ws", ""), items, 4, func(c *Client, item int) (string, error) {
+		return fmt.Sprint(item * 2), nil
+	}))
+
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	seen := make(map[int]bool)
+	for _, result := range results {
+		if result.Err != nil || result.Value != fmt.Sprint(result.Item*2) {
+			t.Errorf("item %d = %q, %v", result.Item, result.Value, result.Err)
+		}
+		seen[result.Item] = true
+	}
+	if len(seen) != len(items) {
+		t.Errorf("got %d distinct items, want %d", len(seen), len(items))
+	}
+}
+
+func TestFetchEachWorkerBound(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   int
+		workers int
+		want    int
+	}{
+		{"bounded by workers", 20, 3, 3},
+		{"bounded by items", 2, 5, 2},
+		{"default workers", 20, 0, DefaultWorkers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak atomic.Int32
+			items := make([]int, tt.items)
+
+			results := collect(t, FetchEach(context.Background(), NewClient("", "", "ws", ""), items, tt.workers, func(c *Client, item int) (int, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return item, nil
+			}))
+
+			if len(results) != tt.items {
+				t.Errorf("got %d results, want %d", len(results), tt.items)
+			}
+			if got := int(peak.Load()); got != tt.want {
+				t.Errorf("peak concurrency = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var started atomic.Int32
+	results := FetchEach(ctx, NewClient("", "", "ws", ""), items, 4, func(c *Client, item int) (int, error) {
+		started.Add(1)
+		if item == 0 {
+			return item, nil
+		}
+		// Stands in for a request, which the cancellation aborts
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	first, ok := <-results
+	if !ok || first.Item != 0 {
+		t.Fatalf("first result = %+v, %v, want item 0", first, ok)
+	}
+	cancel()
+
+	// Results of the requests in flight are dropped once cancelled
+	if rest := collect(t, results); len(rest) != 0 {
+		t.Errorf("got %d results after cancelling, want none", len(rest))
+	}
+	if n := started.Load(); n == int32(len(items)) {
+		t.Errorf("all %d items were fetched despite the cancellation", n)
+	}
+}
+
+func TestFetchEachErrors(t *testing.T) {
+	errOdd := errors.New("odd item")
+	items := []int{1, 2, 3, 4, 5}
+
+	results := collect(t, FetchEach(context.Background(), NewClient("", "", "ws", ""), items, 2, func(c *Client, item int) (int, error) {
+		if item%2 == 1 {
+			return 0, errOdd
+		}
+		return item * 10, nil
+	}))
+
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	for _, result := range results {
+		switch {
+		case result.Item%2 == 1 && !errors.Is(result.Err, errOdd):
+			t.Errorf("item %d error = %v, want %v", result.Item, result.Err, errOdd)
+		case result.Item%2 == 0 && (result.Err != nil || result.Value != result.Item*10):
+			t.Errorf("item %d = %d, %v, want %d", result.Item, result.Value, result.Err, result.Item*10)
+		}
+	}
+}
+
+func TestFetchAcross(t *testing.T) {
+	client := NewClient("", "", "ws", "")
+	results := collect(t, client.FetchAcross(context.Background(), []string{"app", "web", "broken"}, 2, func(c *Client, repoSlug string) ([]PR, error) {
+		if repoSlug == "broken" {
+			return nil, errors.New("not found")
+		}
+		return []PR{{Title: repoSlug}}, nil
+	}))
+
+	got := make(map[string]RepoResult)
+	for _, result := range results {
+		got[result.Item] = result
+	}
+	if len(got) != 3 {
+		t.Fatalf("got results for %d repositories, want 3", len(got))
+	}
+	for _, repo := range []string{"app", "web"} {
+		if r := got[repo]; r.Err != nil || len(r.Value) != 1 || r.Value[0].Title != repo {
+			t.Errorf("%s = %+v, want its PR", repo, r)
+		}
+	}
+	if got["broken"].Err == nil {
+		t.Errorf("broken succeeded, want its error")
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// Task states
+const (
+	TaskResolved   = "RESOLVED"
+	TaskUnresolved = "UNRESOLVED"
+)
+
+// Task is a checklist item on a pull request, optionally attached to a comment
+type Task struct {
+	ID         int          `json:"id"`
+	State      string       `json:"state"`
+	Content    Content      `json:"content"`
+	Creator    AuthorInfo   `json:"creator"`
+	CreatedOn  time.Time    `json:"created_on"`
+	UpdatedOn  time.Time    `json:"updated_on"`
+	ResolvedBy *AuthorInfo  `json:"resolved_by,omitempty"`
+	Comment    *CommentLink `json:"comment,omitempty"`
+}
+
+// Resolved reports whether the task is done
+func (t Task) Resolved() bool {
+	return t.State == TaskResolved
+}
+
+// taskRequest is the body of task creations and updates
+type taskRequest struct {
+	Content *Content     `json:"content,omitempty"`
+	Comment *CommentLink `json:"comment,omitempty"`
+	State   string       `json:"state,omitempty"`
+}
+
+func (c *Client) tasksURL(repoSlug string, id int) string {
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/tasks", c.baseURL, c.workspace, c.repoSlug(repoSlug), id)
+}
+
+// ListPRTasks fetches every task of a pull request, following pagination
+func (c *Client) ListPRTasks(repoSlug string, id int) ([]Task, error) {
+	tasks, err := getAll[Task](c, c.tasksURL(repoSlug, id)+"?pagelen=100", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks for PR #%d: %w", id, err)
+	}
+
+	return tasks, nil
+}
+
+// CreatePRTask adds a task to a pull request, attached to a comment when commentID is not 0
+func (c *Client) CreatePRTask(repoSlug string, id int, content string, commentID int) (*Task, error) {
+	body := taskRequest{Content: &Content{Raw: content}}
+	if commentID != 0 {
+		body.Comment = &CommentLink{ID: commentID}
+	}
+
+	var task Task
+	if err := c.send("POST", c.tasksURL(repoSlug, id), body, &task); err != nil {
+		return nil, fmt.Errorf("failed to create task on PR #%d: %w", id, err)
+	}
+
+	return &task, nil
+}
+
+// UpdatePRTask changes the content or state of a task; empty values are left as they are
+func (c *Client) UpdatePRTask(repoSlug string, id, taskID int, content, state string) (*Task, error) {
+	body := taskRequest{State: state}
+	if content != "" {
+		body.Content = &Content{Raw: content}
+	}
+
+	var task Task
+	if err := c.send("PUT", fmt.Sprintf("%s/%d", c.tasksURL(repoSlug, id), taskID), body, &task); err != nil {
+		return nil, fmt.Errorf("failed to update task %d on PR #%d: %w", taskID, id, err)
+	}
+
+	return &task, nil
+}
+
+// DeletePRTask removes a task from a pull request
+func (c *Client) DeletePRTask(repoSlug string, id, taskID int) error {
+	if err := c.send("DELETE", fmt.Sprintf("%s/%d", c.tasksURL(repoSlug, id), taskID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete task %d on PR #%d: %w", taskID, id, err)
+	}
+
+	return nil
+}
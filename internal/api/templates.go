@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Template is a pull request description template
+type Template struct {
+	Name string
+	Body string
+}
+
+// Template locations in a repository: a single file, or a directory holding several
+const (
+	templateFile = ".bitbucket/pull_request_template.md"
+	templateDir  = ".bitbucket/PULL_REQUEST_TEMPLATE"
+)
+
+// isNotFound reports whether err is a 404 response
+func isNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// FetchFile fetches the content of a file of a repository at a branch, tag or commit
+func (c *Client) FetchFile(repoSlug, ref, filePath string) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s", c.baseURL, c.workspace, c.repoSlug(repoSlug), ref, filePath)
+
+	body, err := c.getRaw(url, "text/plain")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", filePath, err)
+	}
+
+	return string(body), nil
+}
+
+// ListPRTemplates fetches the description templates of a repository at a
+// ref: .bitbucket/pull_request_template.md and the markdown files of
+// .bitbucket/PULL_REQUEST_TEMPLATE/. A repository without templates has none
+func (c *Client) ListPRTemplates(repoSlug, ref string) ([]Template, error) {
+	var templates []Template
+
+	body, err := c.FetchFile(repoSlug, ref, templateFile)
+	switch {
+	case err == nil:
+		templates = append(templates, Template{Name: path.Base(templateFile), Body: body})
+	case !isNotFound(err):
+		return nil, err
+	}
+
+	type entry struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	url := fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s/?pagelen=100", c.baseURL, c.workspace, c.repoSlug(repoSlug), ref, templateDir)
+	entries, err := getAll[entry](c, url, 0)
+	if isNotFound(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.Type != "commit_file" || !strings.EqualFold(path.Ext(e.Path), ".md") {
+			continue
+		}
+		body, err := c.FetchFile(repoSlug, ref, e.Path)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, Template{Name: path.Base(e.Path), Body: body})
+	}
+
+	return templates, nil
+}
+
+// FetchMainBranch returns the name of the main branch of a repository
+func (c *Client) FetchMainBranch(repoSlug string) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/%s?fields=mainbranch.name", c.baseURL, c.workspace, c.repoSlug(repoSlug))
+
+	var repo struct {
+		MainBranch Branch `json:"mainbranch"`
+	}
+	if err := c.get(url, &repo); err != nil {
+		return "", fmt.Errorf("failed to fetch main branch: %w", err)
+	}
+
+	return repo.MainBranch.Name, nil
+}
+
+// ListBranchCommits fetches the commits of a branch that are not on another
+// branch, newest first, up to limit
+func (c *Client) ListBranchCommits(repoSlug, branch, exclude string, limit int) ([]Commit, error) {
+	query := url.Values{}
+	query.Set("include", branch)
+	query.Set("exclude", exclude)
+	query.Set("pagelen", "50")
+	commitsURL := fmt.Sprintf("%s/repositories/%s/%s/commits?%s", c.baseURL, c.workspace, c.repoSlug(repoSlug), query.Encode())
+
+	commits, err := getAll[Commit](c, commitsURL, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits of %s: %w", branch, err)
+	}
+
+	return commits, nil
+}
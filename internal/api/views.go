@@ -0,0 +1,142 @@
+package api
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// ViewFilter selects the pull requests of a saved view across repositories
+type ViewFilter struct {
+	// Repos are globs of the repository slugs queried, all when empty
+	Repos []string
+	// States filters by PR state, open PRs only when empty
+	States []string
+	// Authors and Reviewers match accounts by UUID, nickname or display name,
+	// case-insensitively; a PR must match one of each list that is not empty
+	Authors   []string
+	Reviewers []string
+	// Query is an extra BBQL filter sent with every request
+	Query string
+}
+
+// MatchesRepo reports whether a repository is queried by the view
+func (f ViewFilter) MatchesRepo(slug string) bool {
+	if len(f.Repos) == 0 {
+		return true
+	}
+	for _, glob := range f.Repos {
+		if matched, err := path.Match(glob, slug); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a pull request passes the author and reviewer filters
+func (f ViewFilter) Matches(pr PR) bool {
+	if len(f.Authors) > 0 && !matchesAccount(f.Authors, pr.Author.UUID, pr.Author.Nickname, pr.Author.FullName) {
+		return false
+	}
+	if len(f.Reviewers) > 0 && !slices.ContainsFunc(pr.Reviewers, func(r Reviewer) bool {
+		return matchesAccount(f.Reviewers, r.UUID, r.Nickname, r.FullName)
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchesAccount reports whether the account is one of people
+func matchesAccount(people []string, uuid, nickname, name string) bool {
+	for _, p := range people {
+		if p == uuid || strings.EqualFold(p, nickname) || strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxViewPRs caps the PRs a view fetches from each repository, most recently
+// updated first, so views of merged or declined PRs do not page through the
+// whole history of every repository
+const maxViewPRs = 100
+
+// ViewQuery returns how the PRs of a view are fetched from each repository it
+// matches, to run with FetchAcross. Authors and reviewers are resolved to UUIDs
+// among the workspace members and sent in the BBQL filter; a list naming
+// someone who cannot be resolved is only applied to the fetched PRs
+func (c *Client) ViewQuery(f ViewFilter) RepoQuery {
+	opts := PRListOptions{States: f.States, Limit: maxViewPRs, Sort: "-updated_on"}
+	if len(opts.States) == 0 {
+		opts.States = []string{"OPEN"}
+	}
+
+	var members []AuthorInfo
+	if needsMembers(f.Authors) || needsMembers(f.Reviewers) {
+		// Without the members, the people are only matched on the fetched PRs
+		members, _ = c.ListWorkspaceMembers()
+	}
+
+	var clauses []string
+	if f.Query != "" {
+		clauses = append(clauses, "("+f.Query+")")
+	}
+	if uuids, ok := resolveAccounts(f.Authors, members); ok {
+		clauses = append(clauses, anyOf("author.uuid", uuids))
+	}
+	if uuids, ok := resolveAccounts(f.Reviewers, members); ok {
+		clauses = append(clauses, anyOf("reviewers.uuid", uuids))
+	}
+	opts.Query = strings.Join(clauses, " AND ")
+
+	return func(c *Client, slug string) ([]PR, error) {
+		prs, err := c.ListPRs(slug, opts)
+		if err != nil {
+			return nil, err
+		}
+		return filterPRs(prs, f.Matches), nil
+	}
+}
+
+// isUUID reports whether an account is given by its UUID, such as {3f2a...}
+func isUUID(person string) bool {
+	return strings.HasPrefix(person, "{") && strings.HasSuffix(person, "}")
+}
+
+// needsMembers reports whether people names accounts other than by UUID
+func needsMembers(people []string) bool {
+	return slices.ContainsFunc(people, func(p string) bool { return !isUUID(p) })
+}
+
+// resolveAccounts returns the UUIDs of people, looking names up among members.
+// It fails when the list is empty or someone cannot be found
+func resolveAccounts(people []string, members []AuthorInfo) ([]string, bool) {
+	var uuids []string
+	for _, p := range people {
+		if isUUID(p) {
+			uuids = append(uuids, p)
+			continue
+		}
+
+		found := false
+		for _, m := range members {
+			if matchesAccount([]string{p}, m.UUID, m.Nickname, m.FullName) {
+				uuids = append(uuids, m.UUID)
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return uuids, len(uuids) > 0
+}
+
+// anyOf is a BBQL clause matching a field against any of the values
+func anyOf(field string, values []string) string {
+	terms := make([]string, len(values))
+	for i, v := range values {
+		terms[i] = field + "=" + bbqlString(v)
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
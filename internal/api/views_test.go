@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestViewQuery(t *testing.T) {
+	members := `{"values":[
+		{"user":{"uuid":"{a}","nickname":"alice","display_name":"Alice A"}},
+		{"user":{"uuid":"{b}","nickname":"bob","display_name":"Bob B"}}
+	]}`
+
+	tests := []struct {
+		name       string
+		filter     ViewFilter
+		wantQ      string
+		wantStates []string
+	}{
+		{
+			"defaults to open PRs",
+			ViewFilter{},
+			"", []string{"OPEN"},
+		},
+		{
+			"authors by name and UUID",
+			ViewFilter{Authors: []string{"alice", "{c}"}, States: []string{"MERGED"}},
+			`(author.uuid="{a}" OR author.uuid="{c}")`, []string{"MERGED"},
+		},
+		{
+			"reviewers by display name, case-insensitively",
+			ViewFilter{Reviewers: []string{"bob b"}},
+			`(reviewers.uuid="{b}")`, []string{"OPEN"},
+		},
+		{
+			"extra query and both lists",
+			ViewFilter{Query: `title~"fix"`, Authors: []string{"{a}"}, Reviewers: []string{"bob"}},
+			`(title~"fix") AND (author.uuid="{a}") AND (reviewers.uuid="{b}")`, []string{"OPEN"},
+		},
+		{
+			"unknown person leaves the list to the client",
+			ViewFilter{Authors: []string{"alice", "mallory"}, Reviewers: []string{"bob"}},
+			`(reviewers.uuid="{b}")`, []string{"OPEN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /workspaces/ws/members", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(members))
+			})
+			mux.HandleFunc("GET /repositories/ws/app/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query()
+				_, _ = w.Write([]byte(`{"values":[]}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			client := NewClient("", "", "ws", "")
+			client.baseURL = server.URL
+
+			if _, err := client.ViewQuery(tt.filter)(client, "app"); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+
+			if q := got.Get("q"); q != tt.wantQ {
+				t.Errorf("q = %s, want %s", q, tt.wantQ)
+			}
+			if states := got["state"]; len(states) != len(tt.wantStates) || states[0] != tt.wantStates[0] {
+				t.Errorf("state = %v, want %v", states, tt.wantStates)
+			}
+			if sort := got.Get("sort"); sort != "-updated_on" {
+				t.Errorf("sort = %q, want -updated_on", sort)
+			}
+		})
+	}
+}
+
+func TestViewQueryLimit(t *testing.T) {
+	pages := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		// Every page is full and links to another, like a long PR history
+		body := `{"values":[`
+		for i := range 50 {
+			if i > 0 {
+				body += ","
+			}
+			body += `{"id":1}`
+		}
+		body += `],"next":"` + server.URL + `/repositories/ws/app/pullrequests?page=next"}`
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient("", "", "ws", "")
+	client.baseURL = server.URL
+
+	prs, err := client.ViewQuery(ViewFilter{States: []string{"MERGED"}})(client, "app")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(prs) != maxViewPRs || pages != maxViewPRs/50 {
+		t.Errorf("got %d PRs in %d pages, want %d in %d", len(prs), pages, maxViewPRs, maxViewPRs/50)
+	}
+}
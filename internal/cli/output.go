@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatTSV   = "tsv"
+)
+
+var formats = []string{FormatTable, FormatJSON, FormatYAML, FormatTSV}
+
+// OutputOptions holds the --output and --jq flags of a command
+type OutputOptions struct {
+	Format string
+	JQ     string
+}
+
+func addOutputFlags(cmd *cobra.Command, opts *OutputOptions) {
+	cmd.Flags().StringVarP(&opts.Format, "output", "o", FormatTable, "output format: "+strings.Join(formats, "|"))
+	cmd.Flags().StringVar(&opts.JQ, "jq", "", "select fields with a jq-style expression, e.g. '.[] | {id, title}'")
+}
+
+// validate checks the flags before any request is made
+func (o OutputOptions) validate() error {
+	valid := false
+	for _, f := range formats {
+		if o.Format == f {
+			valid = true
+		}
+	}
+	if !valid {
+		return usageError(fmt.Errorf("invalid output format %q (expected %s)", o.Format, strings.Join(formats, ", ")))
+	}
+
+	if o.JQ != "" {
+		if _, err := ParseQuery(o.JQ); err != nil {
+			return usageError(fmt.Errorf("invalid --jq expression: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// Field is a table column, extracted from each record with a jq-style path
+type Field struct {
+	Header string
+	Path   string
+}
+
+// Print writes data in the requested format. Without --jq, table and tsv output
+// use fields as columns for lists and as rows for a single record
+func Print(w io.Writer, data any, opts OutputOptions, fields []Field) error {
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+
+	if opts.JQ != "" {
+		q, err := ParseQuery(opts.JQ)
+		if err != nil {
+			return usageError(fmt.Errorf("invalid --jq expression: %w", err))
+		}
+		results, err := q.Apply(generic)
+		if err != nil {
+			return fmt.Errorf("--jq: %w", err)
+		}
+		return printResults(w, results, opts.Format)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		return writeJSON(w, generic)
+	case FormatYAML:
+		return writeYAML(w, generic)
+	}
+
+	if list, ok := generic.([]any); ok {
+		rows := make([][]string, len(list))
+		for i, record := range list {
+			rows[i] = make([]string, len(fields))
+			for j, field := range fields {
+				rows[i][j], err = extract(record, field.Path)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		headers := make([]string, len(fields))
+		for i, field := range fields {
+			headers[i] = field.Header
+		}
+		return writeRows(w, opts.Format, headers, rows)
+	}
+
+	rows := make([][]string, len(fields))
+	for i, field := range fields {
+		value, err := extract(generic, field.Path)
+		if err != nil {
+			return err
+		}
+		rows[i] = []string{field.Header, value}
+	}
+	return writeRows(w, opts.Format, nil, rows)
+}
+
+// printResults writes the results of a --jq query. Objects become table rows
+// with their keys as headers, any other value is written one per line
+func printResults(w io.Writer, results []any, format string) error {
+	switch format {
+	case FormatJSON:
+		for _, result := range results {
+			if err := writeJSON(w, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		for i, result := range results {
+			if i > 0 {
+				fmt.Fprintln(w, "---")
+			}
+			if err := writeYAML(w, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var headers []string
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, k := range objectKeys(result) {
+			if !seen[k] {
+				seen[k] = true
+				headers = append(headers, k)
+			}
+		}
+	}
+
+	if len(headers) == 0 {
+		for _, result := range results {
+			fmt.Fprintln(w, formatCell(result, format))
+		}
+		return nil
+	}
+
+	rows := make([][]string, len(results))
+	for i, result := range results {
+		rows[i] = make([]string, len(headers))
+		for j, h := range headers {
+			rows[i][j] = formatCell(objectValue(result, h), format)
+		}
+	}
+
+	upper := make([]string, len(headers))
+	for i, h := range headers {
+		upper[i] = strings.ToUpper(h)
+	}
+	return writeRows(w, format, upper, rows)
+}
+
+func writeRows(w io.Writer, format string, headers []string, rows [][]string) error {
+	if format == FormatTSV {
+		if headers != nil {
+			fmt.Fprintln(w, strings.Join(headers, "\t"))
+		}
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if headers != nil {
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+func writeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlValue(v)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// yamlValue converts json.Number, which the YAML encoder writes as a string,
+// into a proper integer or float throughout v
+func yamlValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = yamlValue(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = yamlValue(item)
+		}
+		return out
+	case *Object:
+		out := &Object{Keys: val.Keys, Values: make(map[string]any, len(val.Values))}
+		for k, item := range val.Values {
+			out.Values[k] = yamlValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// toGeneric converts API structs into plain JSON values so they can be
+// queried by their JSON field names
+func toGeneric(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return generic, nil
+}
+
+func extract(record any, path string) (string, error) {
+	q, err := ParseQuery(path)
+	if err != nil {
+		return "", err
+	}
+	values, err := q.Apply(record)
+	if err != nil {
+		return "", err
+	}
+
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = formatCell(v, FormatTable)
+	}
+	return strings.Join(cells, ", "), nil
+}
+
+func objectKeys(v any) []string {
+	switch val := v.(type) {
+	case *Object:
+		return val.Keys
+	case map[string]any:
+		return sortedKeys(val)
+	}
+	return nil
+}
+
+func objectValue(v any, key string) any {
+	switch val := v.(type) {
+	case *Object:
+		return val.Values[key]
+	case map[string]any:
+		return val[key]
+	}
+	return nil
+}
+
+// formatCell renders a value as a single table cell
+func formatCell(v any, format string) string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		s = ""
+	case string:
+		s = val
+	case json.Number:
+		s = val.String()
+	case bool:
+		s = fmt.Sprintf("%t", val)
+	case []any:
+		cells := make([]string, len(val))
+		for i, item := range val {
+			cells[i] = formatCell(item, format)
+		}
+		s = strings.Join(cells, ", ")
+	default:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			s = fmt.Sprint(val)
+		} else {
+			s = string(raw)
+		}
+	}
+
+	// Keep each record on one line so the output stays line-oriented
+	return strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+)
+
+var prStates = []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}
+
+var prListFields = []Field{
+	{Header: "ID", Path: ".id"},
+	{Header: "TITLE", Path: ".title"},
+	{Header: "AUTHOR", Path: ".author.display_name"},
+	{Header: "STATE", Path: ".state"},
+	{Header: "SOURCE", Path: ".source.branch.name"},
+	{Header: "DESTINATION", Path: ".destination.branch.name"},
+	{Header: "UPDATED", Path: ".updated_on"},
+}
+
+var prViewFields = []Field{
+	{Header: "ID", Path: ".id"},
+	{Header: "TITLE", Path: ".title"},
+	{Header: "STATE", Path: ".state"},
+	{Header: "AUTHOR", Path: ".author.display_name"},
+	{Header: "SOURCE", Path: ".source.branch.name"},
+	{Header: "DESTINATION", Path: ".destination.branch.name"},
+	{Header: "REVIEWERS", Path: ".reviewers[].display_name"},
+	{Header: "COMMENTS", Path: ".comment_count"},
+	{Header: "TASKS", Path: ".task_count"},
+	{Header: "CREATED", Path: ".created_on"},
+	{Header: "UPDATED", Path: ".updated_on"},
+	{Header: "URL", Path: ".links.html.href"},
+	{Header: "DESCRIPTION", Path: ".description"},
+}
+
+func newPRCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Query pull requests",
+	}
+
+	cmd.AddCommand(newPRListCmd(), newPRViewCmd(), newPRDiffCmd())
+	return cmd
+}
+
+func newPRListCmd() *cobra.Command {
+	var (
+		repo   string
+		states []string
+		limit  int
+		output OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List pull requests of a repository",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.validate(); err != nil {
+				return err
+			}
+
+			normalized, err := normalizeStates(states)
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			prs, err := client.ListPRs(repo, api.PRListOptions{States: normalized, Limit: limit})
+			if err != nil {
+				return apiError(err)
+			}
+
+			if prs == nil {
+				prs = []api.PR{}
+			}
+			return Print(cmd.OutOrStdout(), prs, output, prListFields)
+		},
+	}
+
+	cmd.Flags().StringVarP(&repo, "repo", "r", "", "repository slug (defaults to BITBUCKET_REPO)")
+	cmd.Flags().StringSliceVarP(&states, "state", "s", nil, "filter by state: "+strings.Join(prStates, ", ")+" (default OPEN)")
+	cmd.Flags().IntVarP(&limit, "limit", "L", 30, "maximum number of pull requests, 0 for all")
+	addOutputFlags(cmd, &output)
+
+	return cmd
+}
+
+func newPRViewCmd() *cobra.Command {
+	var (
+		repo   string
+		output OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "view <id>",
+		Short: "Show a pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.validate(); err != nil {
+				return err
+			}
+
+			id, err := parsePRID(args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			pr, err := client.FetchPR(repo, id)
+			if err != nil {
+				return apiError(err)
+			}
+
+			return Print(cmd.OutOrStdout(), pr, output, prViewFields)
+		},
+	}
+
+	cmd.Flags().StringVarP(&repo, "repo", "r", "", "repository slug (defaults to BITBUCKET_REPO)")
+	addOutputFlags(cmd, &output)
+
+	return cmd
+}
+
+func newPRDiffCmd() *cobra.Command {
+	var repo string
+
+	cmd := &cobra.Command{
+		Use:   "diff <id>",
+		Short: "Print the unified diff of a pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parsePRID(args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			diff, err := client.FetchPRDiff(repo, id)
+			if err != nil {
+				return apiError(err)
+			}
+
+			_, err = fmt.Fprint(cmd.OutOrStdout(), diff)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&repo, "repo", "r", "", "repository slug (defaults to BITBUCKET_REPO)")
+
+	return cmd
+}
+
+// parsePRID accepts a PR ID with or without a leading '#'
+func parsePRID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id <= 0 {
+		return 0, usageError(fmt.Errorf("invalid pull request ID %q", arg))
+	}
+	return id, nil
+}
+
+func normalizeStates(states []string) ([]string, error) {
+	normalized := make([]string, 0, len(states))
+	for _, state := range states {
+		upper := strings.ToUpper(state)
+		valid := false
+		for _, s := range prStates {
+			if upper == s {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, usageError(fmt.Errorf("invalid state %q (expected %s)", state, strings.Join(prStates, ", ")))
+		}
+		normalized = append(normalized, upper)
+	}
+	return normalized, nil
+}
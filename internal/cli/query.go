@@ -0,0 +1,376 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Query is a compiled jq-style expression. It supports a small subset of jq:
+// paths (".", ".a.b", ".[]", ".[0]", ".items[]"), pipes ("|") and object
+// construction ("{id, author: .author.display_name}")
+type Query struct {
+	stages []stage
+}
+
+type stage interface {
+	eval(v any) ([]any, error)
+}
+
+// ParseQuery compiles a jq-style expression
+func ParseQuery(expr string) (*Query, error) {
+	parts, err := splitTopLevel(expr, '|')
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty stage in query %q", expr)
+		}
+
+		var s stage
+		if strings.HasPrefix(part, "{") {
+			s, err = parseObject(part)
+		} else {
+			s, err = parsePath(part)
+		}
+		if err != nil {
+			return nil, err
+		}
+		q.stages = append(q.stages, s)
+	}
+
+	return q, nil
+}
+
+// Apply runs the query against a decoded JSON value and returns every result
+func (q *Query) Apply(input any) ([]any, error) {
+	stream := []any{input}
+	for _, s := range q.stages {
+		var next []any
+		for _, v := range stream {
+			out, err := s.eval(v)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		stream = next
+	}
+	return stream, nil
+}
+
+// splitTopLevel splits s on sep, ignoring separators nested in braces or brackets
+func splitTopLevel(s string, sep rune) ([]string, error) {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced %q in query %q", r, s)
+			}
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets in query %q", s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// pathStep is one segment of a path: a field name, an index or an iteration
+type pathStep struct {
+	field   string
+	index   int
+	isIndex bool
+	iterate bool
+}
+
+type pathStage struct {
+	steps []pathStep
+}
+
+func parsePath(expr string) (*pathStage, error) {
+	if !strings.HasPrefix(expr, ".") {
+		return nil, fmt.Errorf("path %q must start with '.'", expr)
+	}
+
+	p := &pathStage{}
+	rest := expr[1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' in path %q", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if inner == "" {
+				p.steps = append(p.steps, pathStep{iterate: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, expr)
+				}
+				p.steps = append(p.steps, pathStep{index: n, isIndex: true})
+			}
+			rest = rest[end+1:]
+
+		case rest[0] == '.':
+			rest = rest[1:]
+
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if !isIdentifier(name) {
+				return nil, fmt.Errorf("invalid field %q in path %q", name, expr)
+			}
+			p.steps = append(p.steps, pathStep{field: name})
+			rest = rest[end:]
+		}
+	}
+
+	return p, nil
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && r != '-' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *pathStage) eval(v any) ([]any, error) {
+	stream := []any{v}
+	for _, step := range p.steps {
+		var next []any
+		for _, item := range stream {
+			out, err := step.apply(item)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		stream = next
+	}
+	return stream, nil
+}
+
+func (s pathStep) apply(v any) ([]any, error) {
+	switch {
+	case s.iterate:
+		switch val := v.(type) {
+		case nil:
+			// Unlike jq, iterating over a missing field yields nothing
+			return nil, nil
+		case []any:
+			return val, nil
+		case *Object:
+			out := make([]any, len(val.Keys))
+			for i, k := range val.Keys {
+				out[i] = val.Values[k]
+			}
+			return out, nil
+		case map[string]any:
+			keys := sortedKeys(val)
+			out := make([]any, len(keys))
+			for i, k := range keys {
+				out[i] = val[k]
+			}
+			return out, nil
+		default:
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+		}
+
+	case s.isIndex:
+		switch val := v.(type) {
+		case nil:
+			return []any{nil}, nil
+		case []any:
+			i := s.index
+			if i < 0 {
+				i += len(val)
+			}
+			if i < 0 || i >= len(val) {
+				return []any{nil}, nil
+			}
+			return []any{val[i]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(v))
+		}
+
+	default:
+		switch val := v.(type) {
+		case nil:
+			return []any{nil}, nil
+		case map[string]any:
+			return []any{val[s.field]}, nil
+		case *Object:
+			return []any{val.Values[s.field]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with %q", typeName(v), s.field)
+		}
+	}
+}
+
+type objectField struct {
+	name string
+	path *pathStage
+}
+
+type objectStage struct {
+	fields []objectField
+}
+
+func parseObject(expr string) (*objectStage, error) {
+	if !strings.HasSuffix(expr, "}") {
+		return nil, fmt.Errorf("missing '}' in %q", expr)
+	}
+
+	o := &objectStage{}
+	inner := expr[1 : len(expr)-1]
+	if strings.TrimSpace(inner) == "" {
+		return o, nil
+	}
+
+	parts, err := splitTopLevel(inner, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		name, pathExpr, hasPath := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !isIdentifier(name) {
+			return nil, fmt.Errorf("invalid key %q in %q", name, expr)
+		}
+
+		if !hasPath {
+			pathExpr = "." + name
+		}
+		path, err := parsePath(strings.TrimSpace(pathExpr))
+		if err != nil {
+			return nil, err
+		}
+		o.fields = append(o.fields, objectField{name: name, path: path})
+	}
+
+	return o, nil
+}
+
+// eval builds one object per input. A field whose path yields several values
+// holds them as an array, one that yields none is null
+func (o *objectStage) eval(v any) ([]any, error) {
+	obj := &Object{Values: make(map[string]any, len(o.fields))}
+	for _, field := range o.fields {
+		values, err := field.path.eval(v)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		switch len(values) {
+		case 0:
+		case 1:
+			value = values[0]
+		default:
+			value = values
+		}
+
+		obj.Keys = append(obj.Keys, field.name)
+		obj.Values[field.name] = value
+	}
+	return []any{obj}, nil
+}
+
+// Object is a JSON object that keeps its keys in construction order
+type Object struct {
+	Keys   []string
+	Values map[string]any
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.Values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o *Object) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range o.Keys {
+		var value yaml.Node
+		if err := value.Encode(o.Values[k]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, &value)
+	}
+	return node, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case []any:
+		return "array"
+	case map[string]any, *Object:
+		return "object"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const queryInput = `{
+	"id": 7,
+	"title": "Fix login",
+	"author": {"display_name": "Alice", "nickname": "alice"},
+	"reviewers": [
+		{"display_name": "Bob", "approved": true},
+		{"display_name": "Carol", "approved": false}
+	],
+	"labels": {"b": 2, "a": 1},
+	"draft-state": "ready",
+	"empty": null
+}`
+
+// decode parses JSON the way Print does before running a query
+func decode(t *testing.T, input string) any {
+	t.Helper()
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("invalid input: %v", err)
+	}
+	return v
+}
+
+func TestQueryApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		input string
+		want  string
+	}{
+		{"identity", ".", `{"a":1}`, `[{"a":1}]`},
+		{"field", ".title", queryInput, `["Fix login"]`},
+		{"nested field", ".author.display_name", queryInput, `["Alice"]`},
+		{"field with dash", ".draft-state", queryInput, `["ready"]`},
+		{"missing field", ".nope", queryInput, `[null]`},
+		{"field of missing field", ".nope.deeper", queryInput, `[null]`},
+		{"field of null", ".empty.x", queryInput, `[null]`},
+		{"iterate array", ".reviewers[]", queryInput, `[{"approved":true,"display_name":"Bob"},{"approved":false,"display_name":"Carol"}]`},
+		{"iterate array field", ".reviewers[].display_name", queryInput, `["Bob","Carol"]`},
+		{"iterate root", ".[]", `[1,2,3]`, `[1,2,3]`},
+		{"iterate with dot", ".reviewers.[].approved", queryInput, `[true,false]`},
+		{"iterate with spaces", ".[ ]", `[1,2]`, `[1,2]`},
+		{"iterate object in key order", ".labels[]", queryInput, `[1,2]`},
+		{"iterate null", ".empty[]", queryInput, `[]`},
+		{"iterate empty array", ".[]", `[]`, `[]`},
+		{"index", ".[0]", `["a","b"]`, `["a"]`},
+		{"index field", ".reviewers[1].display_name", queryInput, `["Carol"]`},
+		{"negative index", ".[-1]", `["a","b"]`, `["b"]`},
+		{"index out of range", ".[5]", `["a","b"]`, `[null]`},
+		{"negative index out of range", ".[-3]", `["a","b"]`, `[null]`},
+		{"index null", ".empty[0]", queryInput, `[null]`},
+		{"pipe", ".author | .nickname", queryInput, `["alice"]`},
+		{"pipe after iteration", ".reviewers[] | .display_name", queryInput, `["Bob","Carol"]`},
+		{"pipe chain", ". | .reviewers | .[0] | .approved", queryInput, `[true]`},
+		{"object shorthand", "{id, title}", queryInput, `[{"id":7,"title":"Fix login"}]`},
+		{"object with paths", "{name: .author.display_name, first: .reviewers[0].display_name}", queryInput, `[{"name":"Alice","first":"Bob"}]`},
+		{"object keeps key order", "{title, id}", queryInput, `[{"title":"Fix login","id":7}]`},
+		{"object with several values", "{names: .reviewers[].display_name}", queryInput, `[{"names":["Bob","Carol"]}]`},
+		{"object with no values", "{names: .empty[]}", queryInput, `[{"names":null}]`},
+		{"empty object", "{}", queryInput, `[{}]`},
+		{"empty object with spaces", "{ }", queryInput, `[{}]`},
+		{"object per input", ".reviewers[] | {display_name}", queryInput, `[{"display_name":"Bob"},{"display_name":"Carol"}]`},
+		{"path after object", "{id} | .id", queryInput, `[7]`},
+		{"iterate object built", "{id, title} | .[]", queryInput, `[7,"Fix login"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) failed: %v", tt.expr, err)
+			}
+
+			results, err := q.Apply(decode(t, tt.input))
+			if err != nil {
+				t.Fatalf("Apply(%q) failed: %v", tt.expr, err)
+			}
+			if results == nil {
+				results = []any{}
+			}
+
+			got, err := json.Marshal(results)
+			if err != nil {
+				t.Fatalf("failed to encode results: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Apply(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryApplyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		input   string
+		wantErr string
+	}{
+		{"field of array", ".title", `[1]`, "cannot index array"},
+		{"field of string", ".a.b", `{"a":"x"}`, "cannot index string"},
+		{"index object", ".[0]", `{"a":1}`, "cannot index object with a number"},
+		{"iterate number", ".a[]", `{"a":1}`, "cannot iterate over number"},
+		{"iterate boolean", ".[]", `true`, "cannot iterate over boolean"},
+		{"field in object", "{x: .a.b}", `{"a":[]}`, "cannot index array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) failed: %v", tt.expr, err)
+			}
+
+			_, err = q.Apply(decode(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Apply(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{"empty", "", "empty stage"},
+		{"empty stage", ".a | | .b", "empty stage"},
+		{"trailing pipe", ".a |", "empty stage"},
+		{"no leading dot", "title", "must start with '.'"},
+		{"unclosed bracket", ".[", "unbalanced brackets"},
+		{"unopened bracket", ".a]", "unbalanced"},
+		{"invalid index", ".[x]", "invalid index"},
+		{"invalid field", ".a b", "invalid field"},
+		{"unclosed object", "{id", "unbalanced brackets"},
+		{"text after object", "{id} x", "missing '}'"},
+		{"invalid key", "{a b}", "invalid key"},
+		{"empty key", "{id,}", "invalid key"},
+		{"invalid path in object", "{a: b}", "must start with '.'"},
+		{"pipe in object", "{a: .b | .c}", "invalid field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseQuery(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryExitCodes(t *testing.T) {
+	record := map[string]any{"id": 7, "tags": []string{"a"}}
+
+	tests := []struct {
+		name string
+		opts OutputOptions
+		want int
+	}{
+		{"valid query", OutputOptions{Format: FormatJSON, JQ: "{id}"}, ExitOK},
+		{"parse error", OutputOptions{Format: FormatJSON, JQ: ".["}, ExitUsage},
+		{"evaluation error", OutputOptions{Format: FormatJSON, JQ: ".tags.x"}, ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate()
+			if err == nil {
+				var buf bytes.Buffer
+				err = Print(&buf, record, tt.opts, nil)
+			}
+			if got := ExitCode(err); got != tt.want {
+				t.Errorf("exit code = %d (%v), want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectEncoding(t *testing.T) {
+	q, err := ParseQuery("{title, id}")
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	results, err := q.Apply(decode(t, queryInput))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatJSON, "{\n  \"title\": \"Fix login\",\n  \"id\": 7\n}\n"},
+		{FormatYAML, "title: Fix login\nid: 7\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printResults(&buf, results, tt.format); err != nil {
+				t.Fatalf("printResults failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("printResults(%s) = %q, want %q", tt.format, buf.String(), tt.want)
+			}
+		})
+	}
+}
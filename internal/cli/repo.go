@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+)
+
+var repoListFields = []Field{
+	{Header: "SLUG", Path: ".slug"},
+	{Header: "NAME", Path: ".name"},
+	{Header: "PROJECT", Path: ".project.key"},
+	{Header: "LANGUAGE", Path: ".language"},
+	{Header: "PRIVATE", Path: ".is_private"},
+	{Header: "UPDATED", Path: ".updated_on"},
+	{Header: "URL", Path: ".links.html.href"},
+}
+
+func newRepoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Query repositories",
+	}
+
+	cmd.AddCommand(newRepoListCmd())
+	return cmd
+}
+
+func newRepoListCmd() *cobra.Command {
+	var (
+		role    string
+		project string
+		limit   int
+		output  OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List repositories of the workspace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.validate(); err != nil {
+				return err
+			}
+
+			if !slices.Contains(api.RepoRoles, role) {
+				return usageError(fmt.Errorf("invalid role %q (expected %s)", role, strings.Join(api.RepoRoles, ", ")))
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			repos, err := client.ListRepositories(api.RepoListOptions{Role: role, Project: project, Limit: limit})
+			if err != nil {
+				return apiError(err)
+			}
+
+			if repos == nil {
+				repos = []api.Repository{}
+			}
+			return Print(cmd.OutOrStdout(), repos, output, repoListFields)
+		},
+	}
+
+	cmd.Flags().StringVar(&role, "role", "member", "minimum role: "+strings.Join(api.RepoRoles, ", "))
+	cmd.Flags().StringVarP(&project, "project", "p", "", "only repositories of the project with this key")
+	cmd.Flags().IntVarP(&limit, "limit", "L", 0, "maximum number of repositories, 0 for all")
+	addOutputFlags(cmd, &output)
+
+	return cmd
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anasalqoyyum/lazy-bb/internal/api"
+	"github.com/anasalqoyyum/lazy-bb/internal/config"
+)
+
+// Exit codes returned by lazy-bb
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitConfig   = 3
+	ExitAPI      = 4
+	ExitNotFound = 5
+)
+
+// exitError carries the exit code a command failed with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// WithExitCode attaches an exit code to err
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+func usageError(err error) error {
+	return WithExitCode(ExitUsage, err)
+}
+
+// apiError maps API failures to exit codes, 404s are reported as not found
+func apiError(err error) error {
+	var statusErr *api.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == 404 {
+		return WithExitCode(ExitNotFound, err)
+	}
+	return WithExitCode(ExitAPI, err)
+}
+
+// ExitCode returns the exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
+
+// Execute runs the command line, starting the TUI through runTUI when no
+// subcommand is given, and returns the process exit code
+func Execute(runTUI func(cfg *config.Config) error) int {
+	root := &cobra.Command{
+		Use:           "lazy-bb",
+		Short:         "A terminal UI for Bitbucket pull requests",
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return runTUI(cfg)
+		},
+	}
+
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err)
+	})
+
+	root.AddCommand(newPRCmd(), newRepoCmd())
+
+	err := root.Execute()
+	if err != nil {
+		// Cobra reports unknown commands and bad arguments as plain errors
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) {
+			err = usageError(err)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	return ExitCode(err)
+}
+
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, WithExitCode(ExitConfig, fmt.Errorf("configuration error: %w", err))
+	}
+	return cfg, nil
+}
+
+func newClient() (*api.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(cfg.Email, cfg.APIToken, cfg.Workspace, cfg.Repo), nil
+}
@@ -1,32 +1,184 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Email     string
-	APIToken  string
-	Workspace string
-	Project   string
-	Repo      string
+	Email     string `yaml:"-"`
+	APIToken  string `yaml:"-"`
+	Workspace string `yaml:"-"`
+	Project   string `yaml:"-"`
+	Repo      string `yaml:"-"`
+
+	// Keys overrides the default keybindings, mapping an action name
+	// (e.g. "quit", "refresh") to the list of keys that trigger it
+	Keys map[string][]string `yaml:"keys"`
+
+	// RepoRole is the minimum role of the repositories listed in the repo pane:
+	// member, contributor, admin or owner
+	RepoRole string `yaml:"repo_role"`
+
+	// Layout arranges the panes
+	Layout LayoutConfig `yaml:"layout"`
+
+	// Hyperlinks is auto, always or never; auto detects OSC 8 support from the terminal
+	Hyperlinks string `yaml:"hyperlinks"`
+
+	// OpenCommand opens URLs, e.g. "firefox --new-tab {url}"; the URL is
+	// appended when there is no {url} placeholder
+	OpenCommand string `yaml:"open_command"`
+
+	// Jira links issue keys found in PRs to a Jira site
+	Jira JiraConfig `yaml:"jira"`
+
+	// Merge configures merging PRs from lazy-bb
+	Merge MergeConfig `yaml:"merge"`
+
+	// Templates are offered for the description of new PRs, after those of the repository
+	Templates []TemplateConfig `yaml:"templates"`
+
+	// Views are saved PR queries listed in the repo pane after the dashboards
+	Views []ViewConfig `yaml:"views"`
+	// Teams name groups of people, referred to as @name in the views
+	Teams map[string][]string `yaml:"teams"`
+
+	// Columns lists the PR table columns in display order
+	Columns []string `yaml:"columns"`
+
+	// Theme names the color theme, either bundled or one of Themes
+	Theme  string                 `yaml:"theme"`
+	Themes map[string]ThemeConfig `yaml:"themes"`
+}
+
+// ThemeConfig defines a custom theme as color overrides on a bundled base theme
+type ThemeConfig struct {
+	Base    string            `yaml:"base"`
+	Glamour string            `yaml:"glamour"`
+	Colors  map[string]string `yaml:"colors"`
+}
+
+// JiraConfig points issue keys at a Jira site
+type JiraConfig struct {
+	// BaseURL is the site root, e.g. https://acme.atlassian.net
+	BaseURL string `yaml:"base_url"`
+	// KeyPattern is the regular expression issue keys are detected with
+	KeyPattern string `yaml:"key_pattern"`
+	// FetchIssues shows the summary and status of the issues, fetched from BaseURL
+	FetchIssues bool `yaml:"fetch_issues"`
+
+	// Email and APIToken authenticate with Jira, from JIRA_EMAIL and JIRA_TOKEN.
+	// They are never taken from the Bitbucket credentials, which must not be
+	// sent to another host
+	Email    string `yaml:"-"`
+	APIToken string `yaml:"-"`
+}
+
+// MergeConfig sets how PRs are merged
+type MergeConfig struct {
+	// Strategy is merge_commit, squash or fast_forward; empty uses the repository default
+	Strategy string `yaml:"strategy"`
+	// CloseSourceBranch deletes the source branch once merged
+	CloseSourceBranch bool `yaml:"close_source_branch"`
+	// AllowOverride permits merging while merge checks fail, after a confirmation
+	AllowOverride bool `yaml:"allow_override"`
+}
+
+// TemplateConfig is a PR description template, given inline or as a file
+type TemplateConfig struct {
+	Name string `yaml:"name"`
+	// Repos are globs of the repository slugs the template is offered for, all when empty
+	Repos []string `yaml:"repos"`
+	Body  string   `yaml:"body"`
+	// File is read instead of Body; ~ is the home directory and relative
+	// paths are relative to the config file
+	File string `yaml:"file"`
+}
+
+// Text returns the body of the template, reading it from its file if any
+func (t TemplateConfig) Text() (string, error) {
+	if t.File == "" {
+		return t.Body, nil
+	}
+
+	path := t.File
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(path) {
+		configPath, err := FilePath()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(filepath.Dir(configPath), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %q: %w", t.Name, err)
+	}
+	return string(data), nil
+}
+
+// ViewConfig is a saved query gathering PRs across repositories
+type ViewConfig struct {
+	Name string `yaml:"name"`
+	// Repos are globs of the repository slugs queried, all listed repositories when empty
+	Repos []string `yaml:"repos"`
+	// States are PR states such as OPEN or MERGED, OPEN when empty
+	States []string `yaml:"states"`
+	// Authors and Reviewers are UUIDs, nicknames, display names or @team
+	Authors   []string `yaml:"authors"`
+	Reviewers []string `yaml:"reviewers"`
+	// Query is an extra BBQL filter, e.g. destination.branch.name="main"
+	Query string `yaml:"query"`
+	// Sort is the initial order of the PRs, until sorted otherwise
+	Sort SortConfig `yaml:"sort"`
+}
+
+// SortConfig orders the PR table by a column
+type SortConfig struct {
+	Column string `yaml:"column"`
+	Desc   bool   `yaml:"desc"`
+}
+
+// LayoutConfig selects the pane layout and its split
+type LayoutConfig struct {
+	// Mode is auto, side-by-side or stacked
+	Mode string `yaml:"mode"`
+	// Split is the percentage of the screen given to the lists
+	Split int `yaml:"split"`
+	// StackBelow is the terminal width under which auto stacks the panes
+	StackBelow int `yaml:"stack_below"`
 }
 
 func LoadConfig() (*Config, error) {
 	// Try to load .env file if it exists (don't fail if it doesn't)
 	_ = godotenv.Load()
 
-	cfg := &Config{
-		Email:     os.Getenv("BITBUCKET_EMAIL"),
-		APIToken:  os.Getenv("BITBUCKET_TOKEN"),
-		Workspace: os.Getenv("BITBUCKET_WORKSPACE"),
-		Project:   os.Getenv("BITBUCKET_PROJECT"),
-		Repo:      os.Getenv("BITBUCKET_REPO"),
+	cfg := &Config{}
+	if err := loadFile(cfg); err != nil {
+		return nil, err
 	}
 
+	cfg.Email = os.Getenv("BITBUCKET_EMAIL")
+	cfg.APIToken = os.Getenv("BITBUCKET_TOKEN")
+	cfg.Workspace = os.Getenv("BITBUCKET_WORKSPACE")
+	cfg.Project = os.Getenv("BITBUCKET_PROJECT")
+	cfg.Repo = os.Getenv("BITBUCKET_REPO")
+	cfg.Jira.Email = os.Getenv("JIRA_EMAIL")
+	cfg.Jira.APIToken = os.Getenv("JIRA_TOKEN")
+
 	var missingFields []string
 	if cfg.Email == "" {
 		missingFields = append(missingFields, "BITBUCKET_EMAIL")
@@ -47,3 +199,40 @@ func LoadConfig() (*Config, error) {
 
 	return cfg, nil
 }
+
+// FilePath returns the location of the optional config file.
+// LAZY_BB_CONFIG takes precedence, then $XDG_CONFIG_HOME/lazy-bb/config.yaml
+func FilePath() (string, error) {
+	if path := os.Getenv("LAZY_BB_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "lazy-bb", "config.yaml"), nil
+}
+
+// loadFile reads the config file into cfg, a missing file is not an error
+func loadFile(cfg *Config) error {
+	path, err := FilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
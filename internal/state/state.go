@@ -0,0 +1,179 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// State is UI state persisted between runs
+type State struct {
+	// Sorts holds the PR list sort order per repository full name
+	Sorts map[string]Sort `json:"sorts,omitempty"`
+	// Pinned lists the full names of the favorite repositories
+	Pinned []string `json:"pinned,omitempty"`
+	// Recent lists the full names of recently opened repositories, most recent first
+	Recent []string `json:"recent,omitempty"`
+	// Sessions holds where the user left off, per workspace
+	Sessions map[string]Session `json:"sessions,omitempty"`
+	// Seen holds when each PR was last viewed, by PR key
+	Seen map[string]time.Time `json:"seen,omitempty"`
+
+	path string
+}
+
+// maxRecent caps the recently opened repositories that are remembered
+const maxRecent = 10
+
+// seenRetention is how long PR visits are remembered
+const seenRetention = 90 * 24 * time.Hour
+
+// Session is what is restored on startup
+type Session struct {
+	// Source is the repository slug or dashboard that was loaded
+	Source string `json:"source"`
+	// PR is the key of the PR under the cursor
+	PR string `json:"pr,omitempty"`
+	// Pane is the focused pane
+	Pane string `json:"pane,omitempty"`
+}
+
+type Sort struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+// FilePath returns the location of the state file under $XDG_STATE_HOME
+func FilePath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "lazy-bb", "state.json"), nil
+}
+
+// Load reads the state file, returning empty state when it does not exist yet
+func Load() (*State, error) {
+	path, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Save writes the state file, creating its directory if needed
+func (s *State) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
+
+// SortFor returns the saved sort order for a repository
+func (s *State) SortFor(repo string) (Sort, bool) {
+	sort, ok := s.Sorts[repo]
+	return sort, ok
+}
+
+func (s *State) SetSort(repo string, sort Sort) {
+	if s.Sorts == nil {
+		s.Sorts = make(map[string]Sort)
+	}
+	s.Sorts[repo] = sort
+}
+
+// IsPinned reports whether a repository is a favorite
+func (s *State) IsPinned(repo string) bool {
+	return slices.Contains(s.Pinned, repo)
+}
+
+// TogglePin pins or unpins a repository and reports whether it is now pinned
+func (s *State) TogglePin(repo string) bool {
+	if i := slices.Index(s.Pinned, repo); i >= 0 {
+		s.Pinned = slices.Delete(s.Pinned, i, i+1)
+		return false
+	}
+	s.Pinned = append(s.Pinned, repo)
+	return true
+}
+
+// Visit moves a repository to the front of the recently opened list
+func (s *State) Visit(repo string) {
+	if i := slices.Index(s.Recent, repo); i >= 0 {
+		s.Recent = slices.Delete(s.Recent, i, i+1)
+	}
+	s.Recent = append([]string{repo}, s.Recent...)
+	if len(s.Recent) > maxRecent {
+		s.Recent = s.Recent[:maxRecent]
+	}
+}
+
+// SessionFor returns where the user left off in a workspace
+func (s *State) SessionFor(workspace string) (Session, bool) {
+	session, ok := s.Sessions[workspace]
+	return session, ok
+}
+
+func (s *State) SetSession(workspace string, session Session) {
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]Session)
+	}
+	s.Sessions[workspace] = session
+}
+
+// LastSeen returns when a PR was last viewed
+func (s *State) LastSeen(pr string) (time.Time, bool) {
+	seen, ok := s.Seen[pr]
+	return seen, ok
+}
+
+// MarkSeen records a visit to a PR, forgetting visits older than seenRetention
+func (s *State) MarkSeen(pr string, at time.Time) {
+	if s.Seen == nil {
+		s.Seen = make(map[string]time.Time)
+	}
+	for key, seen := range s.Seen {
+		if at.Sub(seen) > seenRetention {
+			delete(s.Seen, key)
+		}
+	}
+	s.Seen[pr] = at
+}
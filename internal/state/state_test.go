@@ -0,0 +1,161 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	s, err := Load()
+	if err != nil {
+		t.Fatalf("Load without a state file failed: %v", err)
+	}
+	if len(s.Pinned) != 0 || len(s.Recent) != 0 || len(s.Sorts) != 0 || len(s.Sessions) != 0 || len(s.Seen) != 0 {
+		t.Errorf("Load without a state file = %+v, want empty state", s)
+	}
+
+	seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.SetSort("ws/app", Sort{Column: "updated", Desc: true})
+	s.TogglePin("ws/app")
+	s.Visit("ws/web")
+	s.SetSession("ws", Session{Source: "app", PR: "ws/app#7", Pane: "detail"})
+	s.MarkSeen("ws/app#7", seen)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "lazy-bb", "state.json")); err != nil {
+		t.Fatalf("state file not written under XDG_STATE_HOME: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if sort, ok := loaded.SortFor("ws/app"); !ok || sort != (Sort{Column: "updated", Desc: true}) {
+		t.Errorf("sort = %+v, %v", sort, ok)
+	}
+	if !loaded.IsPinned("ws/app") {
+		t.Errorf("ws/app is not pinned")
+	}
+	if !slices.Equal(loaded.Recent, []string{"ws/web"}) {
+		t.Errorf("recent = %v, want [ws/web]", loaded.Recent)
+	}
+	if session, ok := loaded.SessionFor("ws"); !ok || session != (Session{Source: "app", PR: "ws/app#7", Pane: "detail"}) {
+		t.Errorf("session = %+v, %v", session, ok)
+	}
+	if at, ok := loaded.LastSeen("ws/app#7"); !ok || !at.Equal(seen) {
+		t.Errorf("last seen = %v, %v, want %v", at, ok, seen)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	path := filepath.Join(dir, "lazy-bb", "state.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"pinned":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "failed to parse state file") {
+		t.Errorf("Load() error = %v, want a parse error", err)
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name  string
+		state string
+		want  string
+	}{
+		{"XDG_STATE_HOME", "/tmp/state", "/tmp/state/lazy-bb/state.json"},
+		{"falls back to the home directory", "", filepath.Join(home, ".local", "state", "lazy-bb", "state.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", tt.state)
+
+			got, err := FilePath()
+			if err != nil {
+				t.Fatalf("FilePath failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FilePath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkSeen(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		wantKept bool
+	}{
+		{"recent visit", time.Hour, true},
+		{"exactly at the retention", seenRetention, true},
+		{"older than the retention", seenRetention + time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &State{}
+			s.MarkSeen("ws/app#1", now.Add(-tt.age))
+			s.MarkSeen("ws/app#2", now)
+
+			if _, ok := s.LastSeen("ws/app#1"); ok != tt.wantKept {
+				t.Errorf("old visit kept = %v, want %v", ok, tt.wantKept)
+			}
+			if at, ok := s.LastSeen("ws/app#2"); !ok || !at.Equal(now) {
+				t.Errorf("new visit = %v, %v, want %v", at, ok, now)
+			}
+		})
+	}
+}
+
+func TestVisit(t *testing.T) {
+	s := &State{}
+	for i := range maxRecent + 2 {
+		s.Visit(fmt.Sprintf("ws/repo-%d", i))
+	}
+
+	if len(s.Recent) != maxRecent {
+		t.Fatalf("got %d recent repositories, want %d", len(s.Recent), maxRecent)
+	}
+	if s.Recent[0] != "ws/repo-11" || s.Recent[maxRecent-1] != "ws/repo-2" {
+		t.Errorf("recent = %v, want repo-11 down to repo-2", s.Recent)
+	}
+
+	// Visiting again moves a repository to the front without repeating it
+	s.Visit("ws/repo-5")
+	if s.Recent[0] != "ws/repo-5" || len(s.Recent) != maxRecent {
+		t.Errorf("recent = %v, want repo-5 first", s.Recent)
+	}
+	count := 0
+	for _, repo := range s.Recent {
+		if repo == "ws/repo-5" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("repo-5 listed %d times, want once", count)
+	}
+}
@@ -0,0 +1,102 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira fetches issues from the REST API of a Jira site
+type Jira struct {
+	baseURL    string
+	email      string
+	apiToken   string
+	httpClient *http.Client
+}
+
+// NewJira returns a client for the Jira site at baseURL, e.g.
+// https://acme.atlassian.net, authenticating with an Atlassian API token
+func NewJira(baseURL, email, apiToken string) *Jira {
+	return &Jira{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		email:      email,
+		apiToken:   apiToken,
+		httpClient: &http.Client{},
+	}
+}
+
+// StatusError is returned when Jira responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Jira returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// FetchIssue fetches the summary and status of an issue
+func (j *Jira) FetchIssue(key string) (*Issue, error) {
+	issueURL := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", j.baseURL, url.PathEscape(key))
+
+	req, err := http.NewRequest("GET", issueURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if j.email != "" || j.apiToken != "" {
+		req.SetBasicAuth(j.email, j.apiToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue %s: %w", key, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch issue %s: %w", key, &StatusError{StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue %s: %w", key, err)
+	}
+
+	return &Issue{
+		Key:      issue.Key,
+		Summary:  issue.Fields.Summary,
+		Status:   issue.Fields.Status.Name,
+		Category: jiraCategory(issue.Fields.Status.StatusCategory.Key),
+	}, nil
+}
+
+// jiraCategory maps the key of a Jira status category to a tracker category
+func jiraCategory(key string) string {
+	switch key {
+	case "done":
+		return CategoryDone
+	case "indeterminate":
+		return CategoryInProgress
+	default:
+		return CategoryTodo
+	}
+}
@@ -0,0 +1,116 @@
+package tracker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeJira serves issues the way the Jira REST API does, for the credentials
+// alice@example.com / secret
+func fakeJira(t *testing.T, issues map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/issue/{key}", func(w http.ResponseWriter, r *http.Request) {
+		if email, token, ok := r.BasicAuth(); !ok || email != "alice@example.com" || token != "secret" {
+			http.Error(w, `{"errorMessages":["unauthorized"]}`, http.StatusUnauthorized)
+			return
+		}
+		if got := r.Header.Get("Accept"); got != "application/json" {
+			t.Errorf("Accept = %q, want application/json", got)
+		}
+		if got := r.URL.Query().Get("fields"); got != "summary,status" {
+			t.Errorf("fields = %q, want summary,status", got)
+		}
+
+		body, ok := issues[r.PathValue("key")]
+		if !ok {
+			http.Error(w, `{"errorMessages":["Issue does not exist"]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestJiraFetchIssue(t *testing.T) {
+	server := fakeJira(t, map[string]string{
+		"ABC-1": `{"key":"ABC-1","fields":{"summary":"Fix login","status":{"name":"In Review","statusCategory":{"key":"indeterminate"}}}}`,
+		"ABC-2": `{"key":"ABC-2","fields":{"summary":"Ship it","status":{"name":"Closed","statusCategory":{"key":"done"}}}}`,
+		"ABC-3": `{"key":"ABC-3","fields":{"summary":"Triage","status":{"name":"Backlog","statusCategory":{"key":"new"}}}}`,
+		"ABC-4": `{"key":"ABC-4","fields":{}}`,
+	})
+
+	tests := []struct {
+		key  string
+		want Issue
+	}{
+		{"ABC-1", Issue{Key: "ABC-1", Summary: "Fix login", Status: "In Review", Category: CategoryInProgress}},
+		{"ABC-2", Issue{Key: "ABC-2", Summary: "Ship it", Status: "Closed", Category: CategoryDone}},
+		{"ABC-3", Issue{Key: "ABC-3", Summary: "Triage", Status: "Backlog", Category: CategoryTodo}},
+		{"ABC-4", Issue{Key: "ABC-4", Category: CategoryTodo}},
+	}
+
+	// A trailing slash on the base URL is tolerated
+	jira := NewJira(server.URL+"/", "alice@example.com", "secret")
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			issue, err := jira.FetchIssue(tt.key)
+			if err != nil {
+				t.Fatalf("FetchIssue(%q) failed: %v", tt.key, err)
+			}
+			if *issue != tt.want {
+				t.Errorf("FetchIssue(%q) = %+v, want %+v", tt.key, *issue, tt.want)
+			}
+		})
+	}
+}
+
+func TestJiraFetchIssueErrors(t *testing.T) {
+	server := fakeJira(t, map[string]string{
+		"ABC-1": `{"key":"ABC-1","fields":{"summary":"Fix login"}}`,
+		"BAD-1": `{"key":`,
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		token      string
+		wantStatus int
+	}{
+		{"missing issue", "ABC-404", "secret", http.StatusNotFound},
+		{"wrong token", "ABC-1", "wrong", http.StatusUnauthorized},
+		{"no credentials", "ABC-1", "", http.StatusUnauthorized},
+		{"invalid JSON", "BAD-1", "secret", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := "alice@example.com"
+			if tt.token == "" {
+				email = ""
+			}
+
+			_, err := NewJira(server.URL, email, tt.token).FetchIssue(tt.key)
+			if err == nil {
+				t.Fatalf("FetchIssue(%q) succeeded, want an error", tt.key)
+			}
+
+			var statusErr *StatusError
+			switch {
+			case tt.wantStatus == 0 && errors.As(err, &statusErr):
+				t.Errorf("FetchIssue(%q) error = %v, want a parse error", tt.key, err)
+			case tt.wantStatus != 0 && !errors.As(err, &statusErr):
+				t.Errorf("FetchIssue(%q) error = %v, want a StatusError", tt.key, err)
+			case tt.wantStatus != 0 && statusErr.StatusCode != tt.wantStatus:
+				t.Errorf("FetchIssue(%q) status = %d, want %d", tt.key, statusErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package tracker
+
+// Issue is a ticket of an issue tracker, as shown next to the PRs mentioning it
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+	// Category groups statuses: todo, in_progress or done
+	Category string
+}
+
+// Categories of issue statuses, shared by every tracker
+const (
+	CategoryTodo       = "todo"
+	CategoryInProgress = "in_progress"
+	CategoryDone       = "done"
+)
+
+// Client fetches issues from an issue tracker
+type Client interface {
+	FetchIssue(key string) (*Issue, error)
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Column describes one column of the PR table
+type Column struct {
+	ID    string
+	Title string
+	// MinWidth is the narrowest the column may be rendered
+	MinWidth int
+	// MaxWidth caps fixed columns, which are otherwise sized to their content
+	MaxWidth int
+	// Flex is the column's share of the slack, 0 means the column is fixed
+	Flex  int
+	Value func(pr PR) string
+	Less  func(a, b PR) bool
+}
+
+// SortOrder is the column and direction the PR list is sorted by
+type SortOrder struct {
+	Column string
+	Desc   bool
+}
+
+var DefaultColumns = []string{"id", "title", "author", "state", "repo"}
+
+var columnDefs = []Column{
+	{
+		ID: "id", Title: "PR#", MinWidth: 3, MaxWidth: 6,
+		Value: func(pr PR) string { return fmt.Sprintf("%d", pr.ID) },
+		Less:  func(a, b PR) bool { return a.ID < b.ID },
+	},
+	{
+		ID: "title", Title: "Title", MinWidth: 10, Flex: 4,
+		Value: func(pr PR) string { return pr.Title },
+		Less:  lessFold(func(pr PR) string { return pr.Title }),
+	},
+	{
+		ID: "author", Title: "Author", MinWidth: 8, Flex: 1,
+		Value: func(pr PR) string { return pr.Author },
+		Less:  lessFold(func(pr PR) string { return pr.Author }),
+	},
+	{
+		ID: "state", Title: "State", MinWidth: 5, MaxWidth: 10,
+		Value: func(pr PR) string { return pr.State },
+		Less:  func(a, b PR) bool { return a.State < b.State },
+	},
+	{
+		ID: "branch", Title: "Branch", MinWidth: 10, Flex: 2,
+		Value: func(pr PR) string { return pr.SourceBranch + " → " + pr.DestBranch },
+		Less:  lessFold(func(pr PR) string { return pr.SourceBranch }),
+	},
+	{
+		ID: "jira", Title: "Jira", MinWidth: 4, MaxWidth: 12,
+		Value: func(pr PR) string { return strings.Join(pr.JiraKeys(), " ") },
+		Less:  lessFold(func(pr PR) string { return strings.Join(pr.JiraKeys(), " ") }),
+	},
+	{
+		ID: "approvals", Title: "Approvals", MinWidth: 3, MaxWidth: 9,
+		Value: func(pr PR) string {
+			if pr.Reviewers == 0 {
+				return fmt.Sprintf("%d", pr.Approvals)
+			}
+			return fmt.Sprintf("%d/%d", pr.Approvals, pr.Reviewers)
+		},
+		Less: func(a, b PR) bool { return a.Approvals < b.Approvals },
+	},
+	{
+		ID: "build", Title: "Build", MinWidth: 5, MaxWidth: 9,
+		Value: func(pr PR) string { return buildLabel(pr.BuildStatus) },
+		Less:  func(a, b PR) bool { return buildRank(a.BuildStatus) < buildRank(b.BuildStatus) },
+	},
+	{
+		ID: "comments", Title: "Comments", MinWidth: 3, MaxWidth: 8,
+		Value: func(pr PR) string { return fmt.Sprintf("%d", pr.CommentCount) },
+		Less:  func(a, b PR) bool { return a.CommentCount < b.CommentCount },
+	},
+	{
+		ID: "tasks", Title: "Tasks", MinWidth: 3, MaxWidth: 5,
+		Value: func(pr PR) string { return fmt.Sprintf("%d", pr.TaskCount) },
+		Less:  func(a, b PR) bool { return a.TaskCount < b.TaskCount },
+	},
+	{
+		ID: "created", Title: "Created", MinWidth: 10, MaxWidth: 10,
+		Value: func(pr PR) string { return pr.CreatedAt.Local().Format(time.DateOnly) },
+		Less:  func(a, b PR) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	},
+	{
+		ID: "updated", Title: "Updated", MinWidth: 7, MaxWidth: 8,
+		Value: func(pr PR) string { return relativeTime(pr.UpdatedAt, time.Now()) },
+		Less:  func(a, b PR) bool { return a.UpdatedAt.Before(b.UpdatedAt) },
+	},
+	{
+		ID: "repo", Title: "Workspace/Repo", MinWidth: 10, Flex: 2,
+		Value: func(pr PR) string { return fmt.Sprintf("%s/%s", pr.Workspace, pr.Repo) },
+		Less:  lessFold(func(pr PR) string { return pr.Workspace + "/" + pr.Repo }),
+	},
+}
+
+// ColumnIDs lists every available column
+func ColumnIDs() []string {
+	ids := make([]string, len(columnDefs))
+	for i, col := range columnDefs {
+		ids[i] = col.ID
+	}
+	return ids
+}
+
+// ParseColumns resolves configured column IDs, falling back to DefaultColumns
+func ParseColumns(ids []string) ([]Column, error) {
+	if len(ids) == 0 {
+		ids = DefaultColumns
+	}
+
+	columns := make([]Column, 0, len(ids))
+	for _, id := range ids {
+		col, ok := findColumn(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", id, strings.Join(ColumnIDs(), ", "))
+		}
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+func findColumn(id string) (Column, bool) {
+	for _, col := range columnDefs {
+		if col.ID == id {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+func lessFold(value func(pr PR) string) func(a, b PR) bool {
+	return func(a, b PR) bool {
+		return strings.ToLower(value(a)) < strings.ToLower(value(b))
+	}
+}
+
+// sortPRs returns a copy of prs ordered by the given sort, keeping the API
+// order for ties and when no sort column is set
+func sortPRs(prs []PR, order SortOrder) []PR {
+	sorted := make([]PR, len(prs))
+	copy(sorted, prs)
+
+	col, ok := findColumn(order.Column)
+	if !ok {
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if order.Desc {
+			return col.Less(sorted[j], sorted[i])
+		}
+		return col.Less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
+const columnSeparator = " │ "
+
+// allocateWidths sizes the columns to fit the available width. Fixed columns
+// get their content width, flexible columns start at their minimum and share
+// whatever is left. Trailing columns are dropped when even the minimums do not fit
+func allocateWidths(columns []Column, prs []PR, order SortOrder, available int) ([]Column, []int) {
+	for len(columns) > 1 {
+		if widths, ok := fitColumns(columns, prs, order, available); ok {
+			return columns, widths
+		}
+		columns = columns[:len(columns)-1]
+	}
+
+	if len(columns) == 0 {
+		return columns, nil
+	}
+	return columns, []int{max(available, 1)}
+}
+
+func fitColumns(columns []Column, prs []PR, order SortOrder, available int) ([]int, bool) {
+	space := available - runewidth.StringWidth(columnSeparator)*(len(columns)-1)
+
+	widths := make([]int, len(columns))
+	natural := make([]int, len(columns))
+	used := 0
+	for i, col := range columns {
+		natural[i] = runewidth.StringWidth(headerLabel(col, order))
+		for _, pr := range prs {
+			natural[i] = max(natural[i], runewidth.StringWidth(col.Value(pr)))
+		}
+
+		if col.Flex == 0 {
+			widths[i] = min(max(natural[i], col.MinWidth), max(col.MaxWidth, col.MinWidth))
+		} else {
+			widths[i] = col.MinWidth
+		}
+		used += widths[i]
+	}
+
+	if used > space {
+		return nil, false
+	}
+
+	// Hand out the slack by flex weight until every flexible column shows
+	// its full content or the slack runs out
+	slack := space - used
+	for slack > 0 {
+		totalFlex := 0
+		for i, col := range columns {
+			if col.Flex > 0 && widths[i] < natural[i] {
+				totalFlex += col.Flex
+			}
+		}
+		if totalFlex == 0 {
+			break
+		}
+
+		given := 0
+		for i, col := range columns {
+			if col.Flex == 0 || widths[i] >= natural[i] {
+				continue
+			}
+			share := max(slack*col.Flex/totalFlex, 1)
+			share = min(share, natural[i]-widths[i], slack-given)
+			widths[i] += share
+			given += share
+			if given == slack {
+				break
+			}
+		}
+		slack -= given
+	}
+
+	// Whatever remains goes to the first flexible column so rows span the pane
+	for i, col := range columns {
+		if col.Flex > 0 {
+			widths[i] += slack
+			break
+		}
+	}
+
+	return widths, true
+}
+
+func headerLabel(col Column, order SortOrder) string {
+	if col.ID != order.Column {
+		return col.Title
+	}
+	if order.Desc {
+		return col.Title + " ▼"
+	}
+	return col.Title + " ▲"
+}
+
+func buildLabel(status string) string {
+	switch status {
+	case "SUCCESSFUL":
+		return "✓ passed"
+	case "FAILED":
+		return "✗ failed"
+	case "INPROGRESS":
+		return "● running"
+	case "STOPPED":
+		return "■ stopped"
+	default:
+		return "-"
+	}
+}
+
+func buildRank(status string) int {
+	switch status {
+	case "FAILED":
+		return 0
+	case "STOPPED":
+		return 1
+	case "INPROGRESS":
+		return 2
+	case "SUCCESSFUL":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// relativeTime formats t as a short age such as "5m ago" or "3d ago"
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dw ago", int(d.Hours()/(24*7)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+	}
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func mustColumns(t *testing.T, ids ...string) []Column {
+	t.Helper()
+
+	columns, err := ParseColumns(ids)
+	if err != nil {
+		t.Fatalf("ParseColumns(%v) failed: %v", ids, err)
+	}
+	return columns
+}
+
+func columnIDs(columns []Column) []string {
+	ids := make([]string, len(columns))
+	for i, col := range columns {
+		ids[i] = col.ID
+	}
+	return ids
+}
+
+func TestAllocateWidths(t *testing.T) {
+	short := []PR{{ID: 12, Title: "Fix login", Author: "Alice", State: "OPEN", Workspace: "ws", Repo: "app"}}
+	long := []PR{{ID: 12, Title: strings.Repeat("x", 40), Author: "Alexandra Longname", State: "OPEN", Workspace: "ws", Repo: "app"}}
+
+	tests := []struct {
+		name        string
+		prs         []PR
+		order       SortOrder
+		available   int
+		wantColumns []string
+		wantWidths  []int
+	}{
+		{
+			"slack beyond the content goes to the first flexible column",
+			short, SortOrder{}, 100,
+			DefaultColumns, []int{3, 58, 8, 5, 14},
+		},
+		{
+			"flexible columns share the slack by weight",
+			long, SortOrder{}, 60,
+			DefaultColumns, []int{3, 17, 10, 5, 13},
+		},
+		{
+			"flexible columns start at their minimum width",
+			long, SortOrder{}, 48,
+			DefaultColumns, []int{3, 10, 8, 5, 10},
+		},
+		{
+			"the sort arrow widens a fixed column",
+			short, SortOrder{Column: "id", Desc: true}, 50,
+			DefaultColumns, []int{5, 10, 8, 5, 10},
+		},
+		{
+			"narrow terminal drops trailing columns",
+			short, SortOrder{}, 40,
+			[]string{"id", "title", "author", "state"}, []int{3, 15, 8, 5},
+		},
+		{
+			"a single column takes what is left",
+			short, SortOrder{}, 5,
+			[]string{"id"}, []int{5},
+		},
+		{
+			"no room at all",
+			short, SortOrder{}, 0,
+			[]string{"id"}, []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, widths := allocateWidths(mustColumns(t, DefaultColumns...), tt.prs, tt.order, tt.available)
+			if ids := columnIDs(columns); !slices.Equal(ids, tt.wantColumns) {
+				t.Errorf("columns = %v, want %v", ids, tt.wantColumns)
+			}
+			if !slices.Equal(widths, tt.wantWidths) {
+				t.Errorf("widths = %v, want %v", widths, tt.wantWidths)
+			}
+		})
+	}
+}
+
+func TestAllocateWidthsFixedColumns(t *testing.T) {
+	columns := mustColumns(t, "state", "tasks", "title")
+	prs := []PR{{State: "SUPERSEDED-BY-ANOTHER", TaskCount: 1, Title: "Fix"}}
+
+	_, widths := allocateWidths(columns, prs, SortOrder{}, 40)
+	// state is capped at its maximum, tasks is sized to its header, and the
+	// title takes the rest
+	if want := []int{10, 5, 19}; !slices.Equal(widths, want) {
+		t.Errorf("widths = %v, want %v", widths, want)
+	}
+}
+
+func TestSortPRs(t *testing.T) {
+	prs := []PR{
+		{ID: 3, Title: "beta", BuildStatus: "SUCCESSFUL", Approvals: 1},
+		{ID: 1, Title: "Alpha", BuildStatus: "", Approvals: 2},
+		{ID: 2, Title: "gamma", BuildStatus: "FAILED", Approvals: 1},
+		{ID: 4, Title: "Beta", BuildStatus: "INPROGRESS", Approvals: 0},
+	}
+
+	tests := []struct {
+		name  string
+		order SortOrder
+		want  []int
+	}{
+		{"unsorted keeps the API order", SortOrder{}, []int{3, 1, 2, 4}},
+		{"unknown column keeps the API order", SortOrder{Column: "nope"}, []int{3, 1, 2, 4}},
+		{"ascending", SortOrder{Column: "id"}, []int{1, 2, 3, 4}},
+		{"descending", SortOrder{Column: "id", Desc: true}, []int{4, 3, 2, 1}},
+		{"text ignores case and keeps ties in order", SortOrder{Column: "title"}, []int{1, 3, 4, 2}},
+		{"builds by severity, unknown last", SortOrder{Column: "build"}, []int{2, 4, 3, 1}},
+		{"descending keeps ties in order", SortOrder{Column: "approvals", Desc: true}, []int{1, 3, 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := sortPRs(prs, tt.order)
+			got := make([]int, len(sorted))
+			for i, pr := range sorted {
+				got[i] = pr.ID
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sortPRs(%+v) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+
+	if prs[0].ID != 3 {
+		t.Errorf("sortPRs reordered its input")
+	}
+}
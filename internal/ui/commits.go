@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Commit is a commit of a PR, as listed on the commits tab
+type Commit struct {
+	// Hash is abbreviated for display, FullHash identifies the commit
+	Hash     string
+	FullHash string
+	Author   string
+	Date     time.Time
+	// Summary is the first line of the message
+	Summary string
+}
+
+// renderCommits builds the commits tab, newest first, marking commits made
+// since the previous visit
+func (p *PRDetail) renderCommits() string {
+	commits, ok := p.commits[p.PR.Version()]
+	switch {
+	case !ok:
+		return "Loading commits..."
+	case commits.err != nil:
+		return ansi.Wrap("Failed to load commits: "+commits.err.Error(), p.contentWidth(), "")
+	case len(commits.values) == 0:
+		return "No commits"
+	}
+
+	now := time.Now()
+	lastSeen := p.lastSeen[p.PR.Key()]
+	isNew := func(c Commit) bool {
+		return !lastSeen.IsZero() && c.Date.After(lastSeen)
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	newStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+
+	var lines []string
+	if !lastSeen.IsZero() {
+		fresh := 0
+		for _, c := range commits.values {
+			if isNew(c) {
+				fresh++
+			}
+		}
+		lines = append(lines, textStyle.Render(fmt.Sprintf("Last visit %s, %d new", relativeTime(lastSeen, now), fresh)), "")
+	}
+
+	const hashWidth, authorWidth = 8, 16
+	messageWidth := max(p.contentWidth()-2-hashWidth-authorWidth-timelineTimeWidth, 1)
+	header := "  " + padString("HASH", hashWidth) + padString("AUTHOR", authorWidth) + padString("DATE", timelineTimeWidth) + "MESSAGE"
+	lines = append(lines, theme.HeaderStyle().Render(padString(header, p.contentWidth())))
+	p.rowsTop = len(lines)
+
+	for i, c := range commits.values {
+		marker := "  "
+		if isNew(c) {
+			marker = "● "
+		}
+		row := marker + padString(c.Hash, hashWidth) + padString(c.Author, authorWidth) +
+			padString(relativeTime(c.Date, now), timelineTimeWidth) + truncateString(c.Summary, messageWidth)
+
+		switch {
+		case i == p.cursors[TabCommits]:
+			row = theme.SelectedStyle().Render(padString(row, p.contentWidth()))
+		case isNew(c):
+			row = newStyle.Render(marker) + row[len(marker):]
+		}
+		lines = append(lines, row)
+	}
+
+	return strings.Join(lines, "\n")
+}
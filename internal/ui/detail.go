@@ -3,111 +3,424 @@ package ui
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
 )
 
+// DetailTab is a view of the selected PR in the detail pane
+type DetailTab int
+
+const (
+	TabOverview DetailTab = iota
+	TabActivity
+	TabCommits
+	TabTasks
+	TabChecks
+)
+
+// detailTabs lists the tabs in the order they are cycled
+var detailTabs = []DetailTab{TabOverview, TabActivity, TabCommits, TabTasks, TabChecks}
+
+func (t DetailTab) String() string {
+	switch t {
+	case TabOverview:
+		return "Overview"
+	case TabActivity:
+		return "Activity"
+	case TabCommits:
+		return "Commits"
+	case TabTasks:
+		return "Tasks"
+	case TabChecks:
+		return "Checks"
+	default:
+		return "unknown"
+	}
+}
+
+// feed is data loaded for a tab, for one version of a PR
+type feed[T any] struct {
+	values []T
+	err    error
+}
+
+// PRDetail shows the selected PR in a scrollable viewport. The overview is
+// rendered once per PR and width, then served from a cache
 type PRDetail struct {
-	PR           *PR
-	Width        int
-	Height       int
-	Focused      bool
-	ScrollOffset int
+	PR      *PR
+	Width   int
+	Height  int
+	Focused bool
+	Tab     DetailTab
+	// Searching is true while the search query is being typed
+	Searching bool
+	// JiraURL links the issue keys of the PR to a Jira site
+	JiraURL string
+
+	viewport viewport.Model
+	search   textinput.Model
+	// lines holds the rendered content, plain the same lines without ANSI codes
+	lines []string
+	plain []string
+	// renderKey identifies what the viewport currently shows
+	renderKey string
+	cache     map[string]string
+	query     string
+	matches   []int
+	match     int
+
+	// requested holds the tab data already asked for, by tab and PR version
+	requested map[string]bool
+	activity  map[string]feed[ActivityEvent]
+	collapsed map[ActivityKind]bool
+	commits   map[string]feed[Commit]
+	tasks     map[string]feed[Task]
+	checks    map[string]feed[MergeCheck]
+	issues    map[string]issueResult
+	// cursors holds the selected row of the tabs listing items, rowsTop the
+	// line of the first row of the tab shown
+	cursors map[DetailTab]int
+	rowsTop int
+	// Composing is true while a new task is being typed
+	Composing bool
+	taskInput textinput.Model
+	// taskComment is the comment the task being typed is attached to
+	taskComment int
+	// lastSeen holds when each PR was viewed before this session
+	lastSeen map[string]time.Time
+	// generation changes whenever loaded data or display options change
+	generation int
 }
 
 func NewPRDetail(width, height int) *PRDetail {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search"
+	search.Cursor.SetMode(cursor.CursorStatic)
+
+	taskInput := textinput.New()
+	taskInput.Prompt = "+ "
+	taskInput.Placeholder = "new task"
+	taskInput.Cursor.SetMode(cursor.CursorStatic)
+
 	return &PRDetail{
-		PR:           nil,
-		Width:        width,
-		Height:       height,
-		Focused:      false,
-		ScrollOffset: 0,
+		PR:        nil,
+		Width:     width,
+		Height:    height,
+		Focused:   false,
+		viewport:  viewport.New(width, height),
+		search:    search,
+		cache:     make(map[string]string),
+		requested: make(map[string]bool),
+		activity:  make(map[string]feed[ActivityEvent]),
+		collapsed: make(map[ActivityKind]bool),
+		commits:   make(map[string]feed[Commit]),
+		tasks:     make(map[string]feed[Task]),
+		checks:    make(map[string]feed[MergeCheck]),
+		issues:    make(map[string]issueResult),
+		cursors:   make(map[DetailTab]int),
+		taskInput: taskInput,
+		lastSeen:  make(map[string]time.Time),
 	}
 }
 
 func (p *PRDetail) SetPR(pr *PR) {
+	if p.PR == nil || pr == nil || p.PR.Key() != pr.Key() {
+		clear(p.cursors)
+	}
 	p.PR = pr
-	p.ScrollOffset = 0
+	p.sync()
 }
 
-func (p *PRDetail) ScrollUp() {
-	if p.ScrollOffset > 0 {
-		p.ScrollOffset--
+// NextTab switches to the following tab
+func (p *PRDetail) NextTab() {
+	p.Tab = detailTabs[(int(p.Tab)+1)%len(detailTabs)]
+	p.sync()
+}
+
+// PrevTab switches to the preceding tab
+func (p *PRDetail) PrevTab() {
+	p.Tab = detailTabs[(int(p.Tab)-1+len(detailTabs))%len(detailTabs)]
+	p.sync()
+}
+
+// Pending reports the tab whose data must be fetched for the shown PR, and
+// records it as requested so it is only reported once
+func (p *PRDetail) Pending() (DetailTab, bool) {
+	if p.PR == nil || p.Tab == TabOverview {
+		return 0, false
+	}
+
+	key := p.Tab.String() + "/" + p.PR.Version()
+	if p.requested[key] {
+		return 0, false
 	}
+	p.requested[key] = true
+	return p.Tab, true
 }
 
-func (p *PRDetail) ScrollDown() {
-	totalLines := p.calculateTotalLines()
-	maxLines := p.Height - 4
-	maxScroll := max(totalLines-maxLines, 0)
-	if p.ScrollOffset < maxScroll {
-		p.ScrollOffset++
+// Invalidate makes tabs fetch their data again, e.g. after a refresh. The
+// data already loaded stays shown until it is replaced
+func (p *PRDetail) Invalidate() {
+	clear(p.requested)
+}
+
+// SetActivity stores the timeline loaded for a PR version
+func (p *PRDetail) SetActivity(version string, events []ActivityEvent, err error) {
+	p.activity[version] = feed[ActivityEvent]{values: events, err: err}
+	p.generation++
+	p.sync()
+}
+
+// SetCommits stores the commits loaded for a PR version
+func (p *PRDetail) SetCommits(version string, commits []Commit, err error) {
+	p.commits[version] = feed[Commit]{values: commits, err: err}
+	p.generation++
+	p.sync()
+}
+
+// SelectedCommit returns the commit under the cursor of the commits tab
+func (p *PRDetail) SelectedCommit() *Commit {
+	if p.PR == nil || p.Tab != TabCommits {
+		return nil
+	}
+	commits := p.commits[p.PR.Version()].values
+	if i := p.cursors[TabCommits]; i < len(commits) {
+		return &commits[i]
 	}
+	return nil
 }
 
-func (p *PRDetail) ScrollUpHalf() {
-	halfPage := (p.Height - 4) / 2
-	if p.ScrollOffset > halfPage {
-		p.ScrollOffset -= halfPage
-	} else {
-		p.ScrollOffset = 0
+// SetTasks stores the tasks loaded for a PR version
+func (p *PRDetail) SetTasks(version string, tasks []Task, err error) {
+	p.tasks[version] = feed[Task]{values: tasks, err: err}
+	p.cursors[TabTasks] = max(min(p.cursors[TabTasks], len(tasks)-1), 0)
+	p.generation++
+	p.sync()
+}
+
+// SelectedTask returns the task under the cursor of the tasks tab
+func (p *PRDetail) SelectedTask() *Task {
+	if p.PR == nil || p.Tab != TabTasks {
+		return nil
+	}
+	tasks := p.tasks[p.PR.Version()].values
+	if i := p.cursors[TabTasks]; i < len(tasks) {
+		return &tasks[i]
 	}
+	return nil
 }
 
-func (p *PRDetail) ScrollDownHalf() {
-	halfPage := (p.Height - 4) / 2
-	totalLines := p.calculateTotalLines()
-	maxLines := p.Height - 4
-	maxScroll := max(totalLines-maxLines, 0)
-	newOffset := p.ScrollOffset + halfPage
-	if newOffset > maxScroll {
-		p.ScrollOffset = maxScroll
-	} else {
-		p.ScrollOffset = newOffset
+// SetChecks stores the merge checks evaluated for a PR version
+func (p *PRDetail) SetChecks(version string, checks []MergeCheck, err error) {
+	p.checks[version] = feed[MergeCheck]{values: checks, err: err}
+	p.generation++
+	p.sync()
+}
+
+// Reload makes a tab fetch the data of a PR version again, keeping what is
+// shown until then
+func (p *PRDetail) Reload(tab DetailTab, version string) {
+	delete(p.requested, tab.String()+"/"+version)
+}
+
+// rowCount is the number of selectable rows of the tab shown
+func (p *PRDetail) rowCount() int {
+	switch p.Tab {
+	case TabCommits:
+		return len(p.commits[p.PR.Version()].values)
+	case TabTasks:
+		return len(p.tasks[p.PR.Version()].values)
+	default:
+		return 0
 	}
 }
 
-func (p *PRDetail) calculateTotalLines() int {
+// moveCursor moves the selection of the tab shown, scrolling to keep it visible
+func (p *PRDetail) moveCursor(delta int) {
+	p.cursors[p.Tab] = max(min(p.cursors[p.Tab]+delta, p.rowCount()-1), 0)
+	p.generation++
+	p.sync()
+
+	line := p.rowsTop + p.cursors[p.Tab]
+	if line < p.viewport.YOffset {
+		p.viewport.SetYOffset(line)
+	} else if line >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(line - p.viewport.Height + 1)
+	}
+}
+
+// ActivityCounts counts the loaded events of the shown PR by kind
+func (p *PRDetail) ActivityCounts() map[ActivityKind]int {
+	counts := make(map[ActivityKind]int)
 	if p.PR == nil {
-		return 0
+		return counts
+	}
+	for _, e := range p.activity[p.PR.Version()].values {
+		counts[e.Kind]++
 	}
+	return counts
+}
 
-	var details bytes.Buffer
+// IsCollapsed reports whether events of a kind are folded in the timeline
+func (p *PRDetail) IsCollapsed(kind ActivityKind) bool {
+	return p.collapsed[kind]
+}
 
-	details.WriteString("[Title]\n")
-	details.WriteString(fmt.Sprintf("  %s\n\n", truncateForDisplay(p.PR.Title, p.Width-6)))
+// ToggleCollapsed folds or unfolds the events of a kind in the timeline
+func (p *PRDetail) ToggleCollapsed(kind ActivityKind) {
+	p.collapsed[kind] = !p.collapsed[kind]
+	p.generation++
+	p.sync()
+}
 
-	details.WriteString(fmt.Sprintf("[PR #%d - %s]\n\n", p.PR.ID, p.PR.State))
+// HasLastSeen reports whether the previous visit to a PR is known this session
+func (p *PRDetail) HasLastSeen(prKey string) bool {
+	_, ok := p.lastSeen[prKey]
+	return ok
+}
 
-	details.WriteString("[Author]\n")
-	details.WriteString(fmt.Sprintf("  %s\n\n", p.PR.Author))
+// SetLastSeen records when a PR was viewed before this session, zero if never
+func (p *PRDetail) SetLastSeen(prKey string, at time.Time) {
+	p.lastSeen[prKey] = at
+	p.generation++
+}
 
-	if p.PR.Workspace != "" && p.PR.Repo != "" {
-		details.WriteString("[Repository]\n")
-		details.WriteString(fmt.Sprintf("  %s/%s\n\n", p.PR.Workspace, p.PR.Repo))
+// ScrollUp scrolls up a line, or selects the previous row on tabs listing items
+func (p *PRDetail) ScrollUp() {
+	p.sync()
+	if p.PR != nil && (p.Tab == TabCommits || p.Tab == TabTasks) {
+		p.moveCursor(-1)
+		return
 	}
+	p.viewport.ScrollUp(1)
+}
 
-	details.WriteString("[Dates]\n")
-	details.WriteString(fmt.Sprintf("  Created: %s\n", p.PR.CreatedOn))
-	details.WriteString(fmt.Sprintf("  Updated: %s\n\n", p.PR.UpdatedOn))
+// ScrollDown scrolls down a line, or selects the next row on tabs listing items
+func (p *PRDetail) ScrollDown() {
+	p.sync()
+	if p.PR != nil && (p.Tab == TabCommits || p.Tab == TabTasks) {
+		p.moveCursor(1)
+		return
+	}
+	p.viewport.ScrollDown(1)
+}
 
-	if p.PR.Description != "" {
-		details.WriteString("[Description]\n")
-		renderedDesc := p.renderMarkdown(p.PR.Description)
-		details.WriteString(fmt.Sprintf("  %s\n\n", renderedDesc))
+func (p *PRDetail) ScrollUpHalf() {
+	p.sync()
+	p.viewport.HalfPageUp()
+}
+
+func (p *PRDetail) ScrollDownHalf() {
+	p.sync()
+	p.viewport.HalfPageDown()
+}
+
+// contentWidth is the width inside the border and horizontal padding
+func (p *PRDetail) contentWidth() int {
+	return max(p.Width-4, 1)
+}
+
+// viewportHeight leaves room for the title, separator and status line
+func (p *PRDetail) viewportHeight() int {
+	return max(p.Height-3, 1)
+}
+
+// maxCachedRenders bounds the render cache, which is dropped when full
+const maxCachedRenders = 200
+
+// sync brings the viewport in line with the current PR and size, rendering
+// the content only when it is not cached yet
+func (p *PRDetail) sync() {
+	p.viewport.Width = p.contentWidth()
+	p.viewport.Height = p.viewportHeight()
+
+	if p.PR == nil {
+		p.renderKey = ""
+		p.lines, p.plain = nil, nil
+		p.viewport.SetContent("")
+		return
 	}
 
-	details.WriteString("[Link]\n")
-	details.WriteString(fmt.Sprintf("  %s\n", truncateForDisplay(p.PR.Links.HTML.Href, p.Width-6)))
+	view := p.Tab.String() + "/" + p.PR.Key() + "@"
+	key := fmt.Sprintf("%s%s/%d/%s", view, p.PR.UpdatedOn, p.contentWidth(), theme.Name)
+	if p.Tab != TabOverview {
+		// Relative times go stale, so other tabs are re-rendered every minute
+		key += fmt.Sprintf("/%d/%d", p.generation, time.Now().Unix()/60)
+	} else {
+		key += fmt.Sprintf("/%d", p.issuesLoaded())
+	}
+	if key == p.renderKey {
+		return
+	}
 
-	content := details.String()
+	sameView := strings.HasPrefix(p.renderKey, view)
+	p.renderKey = key
+
+	var content string
+	switch p.Tab {
+	case TabActivity:
+		content = p.renderActivity()
+	case TabCommits:
+		content = p.renderCommits()
+	case TabTasks:
+		content = p.renderTasks()
+	case TabChecks:
+		content = p.renderChecks()
+	default:
+		var ok bool
+		content, ok = p.cache[key]
+		if !ok {
+			if len(p.cache) >= maxCachedRenders {
+				clear(p.cache)
+			}
+			content = p.render()
+			p.cache[key] = content
+		}
+	}
 
-	wrappedContent := wrapContent(content, p.Width-6)
+	p.lines = strings.Split(content, "\n")
+	p.plain = make([]string, len(p.lines))
+	for i, line := range p.lines {
+		p.plain[i] = ansi.Strip(line)
+	}
 
-	contentLines := strings.Split(wrappedContent, "\n")
-	return len(contentLines)
+	p.findMatches()
+	p.viewport.SetContent(p.highlighted())
+	if !sameView {
+		p.viewport.GotoTop()
+	}
+}
+
+// renderers caches glamour renderers by style and word-wrap width, building one is slow
+var renderers = make(map[string]*glamour.TermRenderer)
+
+func markdownRenderer(width int) (*glamour.TermRenderer, error) {
+	key := fmt.Sprintf("%s/%d", theme.Glamour, width)
+	if renderer, ok := renderers[key]; ok {
+		return renderer, nil
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(theme.Glamour),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	renderers[key] = renderer
+	return renderer, nil
 }
 
 func (p *PRDetail) renderMarkdown(content string) string {
@@ -115,10 +428,7 @@ func (p *PRDetail) renderMarkdown(content string) string {
 		return ""
 	}
 
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(p.Width-6),
-	)
+	renderer, err := markdownRenderer(p.contentWidth())
 	if err != nil {
 		return content
 	}
@@ -131,34 +441,24 @@ func (p *PRDetail) renderMarkdown(content string) string {
 	return strings.TrimRight(rendered, "\n")
 }
 
-func (p *PRDetail) View() string {
-	if p.PR == nil {
-		return lipgloss.NewStyle().
-			Width(p.Width).
-			Height(p.Height).
-			Align(lipgloss.Center, lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("63")).
-			Render("Select a PR to view details")
-	}
-
+// render builds the PR details, wrapped to the content width
+func (p *PRDetail) render() string {
 	var details bytes.Buffer
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7aa2f7"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
 	details.WriteString(titleStyle.Render("Title"))
 	details.WriteString("\n")
-	details.WriteString(fmt.Sprintf("  %s\n\n", truncateForDisplay(p.PR.Title, p.Width-6)))
+	details.WriteString(fmt.Sprintf("  %s\n\n", p.PR.Title))
 
-	statusStyle := lipgloss.NewStyle()
-	switch p.PR.State {
-	case "OPEN":
-		statusStyle = statusStyle.Foreground(lipgloss.Color("#7aa2f7"))
-	case "MERGED":
-		statusStyle = statusStyle.Foreground(lipgloss.Color("#bb9af7"))
-	case "DECLINED":
-		statusStyle = statusStyle.Foreground(lipgloss.Color("#f7768e"))
+	if issues := p.renderIssues(); len(issues) > 0 {
+		details.WriteString(titleStyle.Render("Jira"))
+		details.WriteString("\n")
+		details.WriteString(strings.Join(issues, "\n"))
+		details.WriteString("\n\n")
 	}
 
+	statusStyle := lipgloss.NewStyle().Foreground(theme.StateColor(p.PR.State))
+
 	details.WriteString(titleStyle.Render("PR #" + fmt.Sprintf("%d", p.PR.ID) + " - "))
 	details.WriteString(statusStyle.Render(p.PR.State))
 	details.WriteString("\n\n")
@@ -181,120 +481,271 @@ func (p *PRDetail) View() string {
 	if p.PR.Description != "" {
 		details.WriteString(titleStyle.Render("Description"))
 		details.WriteString("\n")
-		renderedDesc := p.renderMarkdown(p.PR.Description)
-
-		details.WriteString(fmt.Sprintf("  %s\n\n", renderedDesc))
+		// glamour indents the document itself
+		details.WriteString(linkify(p.renderMarkdown(p.PR.Description)))
+		details.WriteString("\n\n")
 	}
 
 	details.WriteString(titleStyle.Render("Link"))
 	details.WriteString("\n")
-	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aa2f7")).Underline(true)
-	details.WriteString(linkStyle.Render(fmt.Sprintf("  %s\n", truncateForDisplay(p.PR.Links.HTML.Href, p.Width-6))))
+	linkStyle := lipgloss.NewStyle().Foreground(theme.Accent).Underline(true)
+	link := linkStyle.Render(ansi.Truncate(p.PR.Links.HTML.Href, max(p.contentWidth()-2, 0), "..."))
+	details.WriteString("  " + Hyperlink(p.PR.Links.HTML.Href, link))
+
+	// Wrap without dropping escape sequences, breaking long words such as URLs
+	wrapped := ansi.Wrap(details.String(), p.contentWidth(), "")
+	return strings.Join(balanceHyperlinks(strings.Split(wrapped, "\n")), "\n")
+}
+
+// renderActivity builds the timeline tab
+func (p *PRDetail) renderActivity() string {
+	activity, ok := p.activity[p.PR.Version()]
+	var content string
+	switch {
+	case !ok:
+		content = "Loading activity..."
+	case activity.err != nil:
+		content = "Failed to load activity: " + activity.err.Error()
+	default:
+		content = renderTimeline(activity.values, p.collapsed, p.lastSeen[p.PR.Key()], time.Now())
+	}
+	return ansi.Wrap(content, p.contentWidth(), "")
+}
 
-	content := details.String()
+// StartSearch focuses the search prompt
+func (p *PRDetail) StartSearch() tea.Cmd {
+	p.Searching = true
+	p.search.SetValue(p.query)
+	p.search.CursorEnd()
+	return p.search.Focus()
+}
 
-	wrappedContent := wrapContent(content, p.Width-6)
+// UpdateSearch handles key presses while the search query is being typed.
+// Enter jumps to the first match, Esc clears the search
+func (p *PRDetail) UpdateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.Searching = false
+		p.search.Blur()
+		p.setQuery(p.search.Value())
+		p.jumpToMatch()
+		return nil
+	case tea.KeyEsc:
+		p.Searching = false
+		p.search.Blur()
+		p.search.Reset()
+		p.setQuery("")
+		return nil
+	}
 
-	contentLines := strings.Split(wrappedContent, "\n")
-	maxLines := p.Height - 4
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	return cmd
+}
 
-	startLine := p.ScrollOffset
-	if startLine >= len(contentLines) {
-		startLine = max(len(contentLines)-maxLines, 0)
+// NextMatch scrolls to the next line matching the search
+func (p *PRDetail) NextMatch() {
+	if len(p.matches) == 0 {
+		return
 	}
+	p.match = (p.match + 1) % len(p.matches)
+	p.jumpToMatch()
+}
 
-	var displayLines []string
-	for i := startLine; i < len(contentLines) && len(displayLines) < maxLines; i++ {
-		displayLines = append(displayLines, contentLines[i])
+// PrevMatch scrolls to the previous line matching the search
+func (p *PRDetail) PrevMatch() {
+	if len(p.matches) == 0 {
+		return
 	}
+	p.match = (p.match - 1 + len(p.matches)) % len(p.matches)
+	p.jumpToMatch()
+}
 
-	displayContent := strings.Join(displayLines, "\n")
+func (p *PRDetail) setQuery(query string) {
+	p.query = strings.TrimSpace(query)
+	p.match = 0
+	p.findMatches()
+	p.viewport.SetContent(p.highlighted())
+}
 
-	borderColor := lipgloss.Color("#565f89")
-	if p.Focused {
-		borderColor = lipgloss.Color("#7aa2f7")
+// findMatches lists the lines containing the query, ignoring case
+func (p *PRDetail) findMatches() {
+	p.matches = nil
+	if p.query == "" {
+		return
+	}
+
+	pattern := p.queryPattern()
+	for i, line := range p.plain {
+		if pattern.MatchString(line) {
+			p.matches = append(p.matches, i)
+		}
+	}
+	if p.match >= len(p.matches) {
+		p.match = 0
+	}
+}
+
+// queryPattern matches the search query literally, ignoring case
+func (p *PRDetail) queryPattern() *regexp.Regexp {
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(p.query))
+}
+
+func (p *PRDetail) jumpToMatch() {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.viewport.SetContent(p.highlighted())
+
+	// Keep a little context above the match
+	p.viewport.SetYOffset(p.matches[p.match] - 2)
+}
+
+// highlighted returns the content with the search matches marked. Matching
+// lines lose their own styling so the highlight lines up with the plain text
+func (p *PRDetail) highlighted() string {
+	if len(p.matches) == 0 {
+		return strings.Join(p.lines, "\n")
+	}
+
+	lines := make([]string, len(p.lines))
+	copy(lines, p.lines)
+
+	matchStyle := lipgloss.NewStyle().Reverse(true)
+	currentStyle := theme.SelectedStyle()
+	pattern := p.queryPattern()
+
+	for n, i := range p.matches {
+		style := matchStyle
+		if n == p.match {
+			style = currentStyle
+		}
+
+		plain := p.plain[i]
+
+		var b strings.Builder
+		pos := 0
+		for _, loc := range pattern.FindAllStringIndex(plain, -1) {
+			b.WriteString(plain[pos:loc[0]])
+			b.WriteString(style.Render(plain[loc[0]:loc[1]]))
+			pos = loc[1]
+		}
+		b.WriteString(plain[pos:])
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// statusLine shows the search prompt, the match position or how far the content is scrolled
+func (p *PRDetail) statusLine() string {
+	style := lipgloss.NewStyle().Foreground(theme.Text)
+
+	switch {
+	case p.Composing:
+		return p.taskInput.View()
+	case p.Searching:
+		return p.search.View()
+	case p.query != "" && len(p.matches) == 0:
+		return style.Render(fmt.Sprintf("/%s  no matches", p.query))
+	case p.query != "":
+		return style.Render(fmt.Sprintf("/%s  [%d/%d]", p.query, p.match+1, len(p.matches)))
+	case p.viewport.TotalLineCount() > p.viewport.Height:
+		return style.Render(fmt.Sprintf("%3.0f%%", p.viewport.ScrollPercent()*100))
+	default:
+		return ""
+	}
+}
+
+func (p *PRDetail) View() string {
+	if p.PR == nil {
+		return lipgloss.NewStyle().
+			Width(p.Width).
+			Height(p.Height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Border).
+			Render("Select a PR to view details")
 	}
 
-	panelTitleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aa2f7"))
+	p.sync()
+
+	panelTitleStyle := lipgloss.NewStyle().Foreground(theme.Accent)
 	if p.Focused {
 		panelTitleStyle = panelTitleStyle.Bold(true)
 	}
-	titleLine := panelTitleStyle.Render("[2]-Details")
+	titleLine := ansi.Truncate(panelTitleStyle.Render("[2]-Details")+"  "+p.tabsLine(), p.contentWidth(), "…")
 
-	separatorLine := lipgloss.NewStyle().Foreground(lipgloss.Color("#565f89")).Render(strings.Repeat("─", p.Width-6))
+	separatorLine := lipgloss.NewStyle().Foreground(theme.Border).Render(strings.Repeat("─", p.contentWidth()))
 
-	finalContent := titleLine + "\n" + separatorLine + "\n" + displayContent
+	finalContent := titleLine + "\n" + separatorLine + "\n" + p.viewport.View() + "\n" + p.statusLine()
 
 	return lipgloss.NewStyle().
 		Width(p.Width).
 		Height(p.Height).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
+		BorderForeground(theme.BorderColor(p.Focused)).
 		Padding(0, 2).
 		Render(finalContent)
 }
 
-func truncateForDisplay(s string, width int) string {
-	if len(s) <= width {
-		return s
-	}
-	if width <= 3 {
-		return "..."
+// tabsLine names the tabs, highlighting the active one
+func (p *PRDetail) tabsLine() string {
+	inactive := lipgloss.NewStyle().Foreground(theme.Text)
+	active := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).Underline(true)
+
+	names := make([]string, len(detailTabs))
+	for i, tab := range detailTabs {
+		style := inactive
+		if tab == p.Tab {
+			style = active
+		}
+		names[i] = style.Render(tab.String())
 	}
-	return s[:width-3] + "..."
+	return strings.Join(names, inactive.Render(" │ "))
 }
 
-// stripANSI removes ANSI escape sequences from a string for length calculation
-func stripANSI(s string) string {
-	ansiRe := strings.NewReplacer(
-		"\033[0m", "",
-		"\033[1m", "",
-		"\033[3m", "",
-		"\033[4m", "",
-		"\033[90m", "", "\033[91m", "", "\033[92m", "", "\033[93m", "",
-		"\033[94m", "", "\033[95m", "", "\033[96m", "", "\033[97m", "",
-	)
-	s = ansiRe.Replace(s)
-	// Remove any remaining color codes
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\033' {
-			// Find the end of the escape sequence
-			end := strings.IndexByte(s[i+1:], 'm')
-			if end != -1 {
-				s = s[:i] + s[i+1+end+1:]
-				i--
-			}
-		}
+// detailContentTop and detailContentLeft locate the first content cell relative
+// to the pane's top-left border corner (border, title and separator / border and padding)
+const (
+	detailContentTop  = 3
+	detailContentLeft = 3
+)
+
+// LinkAt returns the URL rendered at a position relative to the pane's
+// top-left border corner, or "" when there is none
+func (p *PRDetail) LinkAt(x, y int) string {
+	if p.PR == nil {
+		return ""
 	}
-	return s
-}
-
-// wrapContent wraps text content to fit within a given width
-// while preserving existing line breaks and handling multi-line segments
-func wrapContent(content string, width int) string {
-	lines := strings.Split(content, "\n")
-	var wrapped []string
-
-	for _, line := range lines {
-		plainLine := stripANSI(line)
-		if len(plainLine) <= width {
-			wrapped = append(wrapped, line)
-		} else {
-			// For lines with ANSI codes, just keep them as-is but limit output
-			if len(line) > width*2 {
-				wrapped = append(wrapped, line[:min(len(line), width*2)])
-			} else {
-				wrapped = append(wrapped, line)
-			}
-		}
+
+	p.sync()
+	row := y - detailContentTop
+	if row < 0 || row >= p.viewport.Height {
+		return ""
 	}
 
-	return strings.Join(wrapped, "\n")
-}
+	idx := p.viewport.YOffset + row
+	if idx >= len(p.plain) {
+		return ""
+	}
+
+	line := p.plain[idx]
+	col := x - detailContentLeft
+	for _, loc := range urlPattern.FindAllStringIndex(line, -1) {
+		start := runewidth.StringWidth(line[:loc[0]])
+		end := start + runewidth.StringWidth(line[loc[0]:loc[1]])
+		if col < start || col >= end {
+			continue
+		}
 
-func min(a, b int) int {
-	if a < b {
-		return a
+		link := line[loc[0]:loc[1]]
+		// The PR link is shortened for display, so resolve it to the full URL
+		if prefix := strings.TrimSuffix(link, "..."); prefix != link && strings.HasPrefix(p.PR.Links.HTML.Href, prefix) {
+			return p.PR.Links.HTML.Href
+		}
+		return link
 	}
-	return b
+
+	return ""
 }
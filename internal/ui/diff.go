@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DiffView is a full screen overlay showing a unified diff
+type DiffView struct {
+	Title   string
+	Width   int
+	Height  int
+	Visible bool
+	Loading bool
+	// Spec identifies the diff being shown, to drop responses for older requests
+	Spec string
+
+	viewport viewport.Model
+	diff     string
+	err      error
+}
+
+func NewDiffView() *DiffView {
+	return &DiffView{viewport: viewport.New(0, 0)}
+}
+
+// Open shows the viewer while the diff is being fetched
+func (d *DiffView) Open(title, spec string) {
+	d.Title = title
+	d.Spec = spec
+	d.diff = ""
+	d.err = nil
+	d.Loading = true
+	d.Visible = true
+	d.sync()
+}
+
+func (d *DiffView) SetDiff(diff string, err error) {
+	d.diff = diff
+	d.err = err
+	d.Loading = false
+	d.sync()
+	d.viewport.GotoTop()
+}
+
+func (d *DiffView) Close() {
+	d.Visible = false
+}
+
+func (d *DiffView) ScrollUp() {
+	d.viewport.ScrollUp(1)
+}
+
+func (d *DiffView) ScrollDown() {
+	d.viewport.ScrollDown(1)
+}
+
+func (d *DiffView) ScrollUpHalf() {
+	d.viewport.HalfPageUp()
+}
+
+func (d *DiffView) ScrollDownHalf() {
+	d.viewport.HalfPageDown()
+}
+
+// Resize fits the viewer to the terminal
+func (d *DiffView) Resize(width, height int) {
+	d.Width = width
+	d.Height = height
+	d.sync()
+}
+
+func (d *DiffView) contentWidth() int {
+	return max(d.Width-6, 1)
+}
+
+// sync renders the diff into the viewport. Lines are cut rather than wrapped
+// so the columns of the diff stay aligned
+func (d *DiffView) sync() {
+	d.viewport.Width = d.contentWidth()
+	// Leave room for the border, title, separator and hints
+	d.viewport.Height = max(d.Height-5, 1)
+
+	switch {
+	case d.Loading:
+		d.viewport.SetContent("Loading diff...")
+		return
+	case d.err != nil:
+		d.viewport.SetContent(ansi.Wrap("Failed to load diff: "+d.err.Error(), d.contentWidth(), ""))
+		return
+	case d.diff == "":
+		d.viewport.SetContent("No changes")
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(d.diff, "\n"), "\n")
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		lines[i] = diffLineStyle(line).Render(ansi.Truncate(line, d.contentWidth(), "…"))
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// diffLineStyle colors a line of a unified diff by its kind
+func diffLineStyle(line string) lipgloss.Style {
+	switch {
+	case strings.HasPrefix(line, "diff --git"):
+		return lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return lipgloss.NewStyle().Bold(true)
+	case strings.HasPrefix(line, "@@"):
+		return lipgloss.NewStyle().Foreground(theme.Accent)
+	case strings.HasPrefix(line, "+"):
+		return lipgloss.NewStyle().Foreground(theme.Open)
+	case strings.HasPrefix(line, "-"):
+		return lipgloss.NewStyle().Foreground(theme.Declined)
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+func (d *DiffView) View(hints string) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	title := titleStyle.Render(ansi.Truncate(d.Title, d.contentWidth()-6, "…"))
+	if !d.Loading && d.viewport.TotalLineCount() > d.viewport.Height {
+		title += lipgloss.NewStyle().Foreground(theme.Text).Render(fmt.Sprintf(" %3.0f%%", d.viewport.ScrollPercent()*100))
+	}
+
+	separator := lipgloss.NewStyle().Foreground(theme.Border).Render(strings.Repeat("─", d.contentWidth()))
+
+	content := title + "\n" + separator + "\n" + d.viewport.View() + "\n" + hints
+
+	return lipgloss.NewStyle().
+		Width(d.Width-2).
+		Height(d.Height-2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(0, 2).
+		Render(content)
+}
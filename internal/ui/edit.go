@@ -0,0 +1,789 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Person is a Bitbucket account, such as a reviewer
+type Person struct {
+	Name string
+	// Nickname is matched by autocomplete along with the name
+	Nickname string
+	UUID     string
+}
+
+// EditData is what the edit form is filled with once loaded
+type EditData struct {
+	Title       string
+	Description string
+	Reviewers   []Person
+	Destination string
+	// UpdatedAt is when the PR was last updated, to detect concurrent edits
+	UpdatedAt time.Time
+	// People and Branches are suggested for reviewers and the branches
+	People   []Person
+	Branches []string
+	// Author cannot review their own PR, so is never suggested
+	AuthorUUID string
+	// Templates are offered for the description of a new PR
+	Templates []Template
+}
+
+// PRDraft is the edited PR, ready to be saved
+type PRDraft struct {
+	Title       string
+	Description string
+	Reviewers   []Person
+	Destination string
+	// Source and CloseSourceBranch are only set for a new PR
+	Source            string
+	CloseSourceBranch bool
+	// UpdatedAt is zero when the PR is saved regardless of concurrent edits
+	UpdatedAt time.Time
+}
+
+// EditDescriptionMsg asks for the description to be written in an external editor
+type EditDescriptionMsg struct {
+	Description string
+}
+
+// BranchesChangedMsg reports the branches picked for a new PR, whose
+// commits fill in the description template
+type BranchesChangedMsg struct {
+	Source      string
+	Destination string
+}
+
+// editField is an input of the edit form
+type editField int
+
+const (
+	fieldTitle editField = iota
+	fieldDescription
+	fieldReviewers
+	fieldDestination
+	fieldSource
+	fieldTemplate
+	fieldCloseSource
+)
+
+// The fields in the order tab visits them
+var (
+	editFields   = []editField{fieldTitle, fieldDescription, fieldReviewers, fieldDestination}
+	createFields = []editField{fieldSource, fieldDestination, fieldTemplate, fieldTitle, fieldDescription, fieldReviewers, fieldCloseSource}
+)
+
+// maxSuggestions is the number of autocomplete suggestions shown
+const maxSuggestions = 5
+
+// editHints lists the keys of the form, which are not configurable as they
+// must not clash with typing
+const editHints = "tab next · enter pick · ctrl+e editor · ctrl+p preview · ctrl+s save · esc cancel"
+
+// EditForm is a full screen overlay editing the title, description,
+// reviewers and destination branch of a PR, or filling them in for a new
+// PR along with its source branch and description template
+type EditForm struct {
+	// PR is the PR being edited, nil when creating one in Repo
+	PR      *PR
+	Repo    string
+	Width   int
+	Height  int
+	Visible bool
+	Loading bool
+	Saving  bool
+
+	field       editField
+	title       textinput.Model
+	description textarea.Model
+	preview     bool
+	reviewers   []Person
+	reviewer    textinput.Model
+	destination textinput.Model
+	source      textinput.Model
+	closeSource bool
+	suggestion  int
+
+	data EditData
+	// template indexes data.Templates, len(data.Templates) meaning none
+	template int
+	vars     TemplateVars
+	// expanded and autoTitle were filled in last, and are replaced when the
+	// template or the branches change unless edited by hand
+	expanded  string
+	autoTitle string
+
+	// overwrite is set after a conflict so the next save ignores it
+	overwrite bool
+	notice    string
+	err       error
+}
+
+func NewEditForm() *EditForm {
+	newInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = placeholder
+		input.Cursor.SetMode(cursor.CursorStatic)
+		return input
+	}
+
+	description := textarea.New()
+	description.ShowLineNumbers = false
+	description.Prompt = ""
+	description.MaxHeight = 0
+	description.Placeholder = "Description (markdown)"
+	description.Cursor.SetMode(cursor.CursorStatic)
+
+	return &EditForm{
+		title:       newInput("Title"),
+		description: description,
+		reviewer:    newInput("add reviewer"),
+		destination: newInput("branch"),
+		source:      newInput("branch"),
+	}
+}
+
+// Open shows the form for a PR while its current values are being loaded
+func (f *EditForm) Open(pr PR) {
+	f.PR = &pr
+	f.Repo = pr.Repo
+	f.open()
+}
+
+// OpenCreate shows the form for a new PR in a repository while its
+// branches and templates are being loaded
+func (f *EditForm) OpenCreate(repo string) {
+	f.PR = nil
+	f.Repo = repo
+	f.open()
+}
+
+func (f *EditForm) open() {
+	f.Loading = true
+	f.Saving = false
+	f.err = nil
+	f.notice = ""
+	f.overwrite = false
+	f.preview = false
+	f.Visible = true
+}
+
+// Creating reports whether the form fills in a new PR
+func (f *EditForm) Creating() bool {
+	return f.PR == nil
+}
+
+// Target is the PR being edited, or the new PR as far as it is filled in
+func (f *EditForm) Target() PR {
+	if !f.Creating() {
+		return *f.PR
+	}
+	return PR{
+		Repo:         f.Repo,
+		Title:        strings.TrimSpace(f.title.Value()),
+		SourceBranch: strings.TrimSpace(f.source.Value()),
+		DestBranch:   strings.TrimSpace(f.destination.Value()),
+	}
+}
+
+// Load fills the form with the current values of the PR, or the defaults of a new one
+func (f *EditForm) Load(data EditData, err error) tea.Cmd {
+	f.Loading = false
+	f.err = err
+	if err != nil {
+		return nil
+	}
+
+	f.data = data
+	f.title.SetValue(data.Title)
+	f.title.CursorEnd()
+	f.description.SetValue(data.Description)
+	f.reviewers = append([]Person(nil), data.Reviewers...)
+	f.reviewer.Reset()
+	f.destination.SetValue(data.Destination)
+	f.destination.CursorEnd()
+	f.source.Reset()
+	f.closeSource = false
+	f.template = 0
+	f.vars = TemplateVars{Destination: data.Destination}
+	f.expanded, f.autoTitle = data.Description, data.Title
+	f.resize()
+
+	if f.Creating() {
+		f.applyTemplate()
+	}
+	return f.focus(f.fields()[0])
+}
+
+// SetCommits fills the commit summaries of a new PR into its template and
+// default title, unless other branches were picked meanwhile
+func (f *EditForm) SetCommits(source, destination string, commits []string) {
+	if !f.Creating() || source != strings.TrimSpace(f.source.Value()) || destination != strings.TrimSpace(f.destination.Value()) {
+		return
+	}
+	f.vars = TemplateVars{Branch: source, Destination: destination, Commits: commits}
+	f.applyTemplate()
+}
+
+// applyTemplate fills in the selected template and the default title,
+// keeping them when they were edited by hand
+func (f *EditForm) applyTemplate() {
+	body := ""
+	if f.template < len(f.data.Templates) {
+		body = ExpandTemplate(f.data.Templates[f.template].Body, f.vars)
+	}
+	if f.description.Value() == f.expanded {
+		f.description.SetValue(body)
+	}
+	f.expanded = body
+
+	title := DefaultTitle(f.vars)
+	if f.title.Value() == f.autoTitle {
+		f.title.SetValue(title)
+		f.title.CursorEnd()
+	}
+	f.autoTitle = title
+}
+
+func (f *EditForm) Close() {
+	f.Visible = false
+	f.title.Blur()
+	f.description.Blur()
+	f.reviewer.Blur()
+	f.destination.Blur()
+	f.source.Blur()
+}
+
+// SetConflict reports that the PR changed since it was loaded; saving again
+// overwrites those changes
+func (f *EditForm) SetConflict(err error) {
+	f.Saving = false
+	f.overwrite = true
+	f.err = nil
+	f.notice = err.Error() + ". Press ctrl+s again to overwrite, esc to discard your changes"
+}
+
+// SetDescription replaces the description, e.g. with the text written in an external editor
+func (f *EditForm) SetDescription(description string) {
+	f.description.SetValue(description)
+	f.notice = ""
+}
+
+// SetError shows a failed save, keeping the edits
+func (f *EditForm) SetError(err error) {
+	f.SetNotice(err.Error())
+}
+
+// SetNotice shows a message below the fields
+func (f *EditForm) SetNotice(notice string) {
+	f.Saving = false
+	f.notice = notice
+}
+
+// Resize fits the form to the terminal
+func (f *EditForm) Resize(width, height int) {
+	f.Width = width
+	f.Height = height
+	f.resize()
+}
+
+func (f *EditForm) contentWidth() int {
+	return max(f.Width-6, 1)
+}
+
+// descriptionHeight leaves room for the other fields, the suggestions and the hints
+func (f *EditForm) descriptionHeight() int {
+	if f.Creating() {
+		return max(f.Height-31, 3)
+	}
+	return max(f.Height-22, 3)
+}
+
+func (f *EditForm) resize() {
+	f.title.Width = f.contentWidth()
+	f.reviewer.Width = max(f.contentWidth()/2, 10)
+	f.destination.Width = f.contentWidth()
+	f.source.Width = f.contentWidth()
+	f.description.SetWidth(f.contentWidth())
+	f.description.SetHeight(f.descriptionHeight())
+}
+
+func (f *EditForm) fields() []editField {
+	if f.Creating() {
+		return createFields
+	}
+	return editFields
+}
+
+// move focuses the field delta places away. Leaving a branch of a new PR
+// reports the branches when they changed, to load their commits
+func (f *EditForm) move(delta int) tea.Cmd {
+	fields := f.fields()
+	current := 0
+	for i, field := range fields {
+		if field == f.field {
+			current = i
+		}
+	}
+
+	leaving := f.field
+	cmd := f.focus(fields[(current+delta+len(fields))%len(fields)])
+	if !f.Creating() || (leaving != fieldSource && leaving != fieldDestination) {
+		return cmd
+	}
+
+	source, destination := strings.TrimSpace(f.source.Value()), strings.TrimSpace(f.destination.Value())
+	if source == "" || destination == "" || (source == f.vars.Branch && destination == f.vars.Destination) {
+		return cmd
+	}
+	changed := func() tea.Msg { return BranchesChangedMsg{Source: source, Destination: destination} }
+	return tea.Batch(cmd, changed)
+}
+
+// focus moves the cursor to a field
+func (f *EditForm) focus(field editField) tea.Cmd {
+	f.field = field
+	f.suggestion = 0
+	f.title.Blur()
+	f.description.Blur()
+	f.reviewer.Blur()
+	f.destination.Blur()
+	f.source.Blur()
+
+	switch field {
+	case fieldTitle:
+		return f.title.Focus()
+	case fieldDescription:
+		return f.description.Focus()
+	case fieldReviewers:
+		return f.reviewer.Focus()
+	case fieldSource:
+		return f.source.Focus()
+	case fieldTemplate, fieldCloseSource:
+		return nil
+	default:
+		return f.destination.Focus()
+	}
+}
+
+// Update handles key presses while the form is open. It returns the draft
+// to save on ctrl+s
+func (f *EditForm) Update(msg tea.KeyMsg) (tea.Cmd, *PRDraft) {
+	if f.Loading || f.Saving {
+		if msg.Type == tea.KeyEsc {
+			f.Close()
+		}
+		return nil, nil
+	}
+	if f.err != nil {
+		if msg.Type == tea.KeyEsc || msg.Type == tea.KeyEnter {
+			f.Close()
+		}
+		return nil, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		f.Close()
+		return nil, nil
+	case tea.KeyCtrlS:
+		return nil, f.draft()
+	case tea.KeyTab:
+		return f.move(1), nil
+	case tea.KeyShiftTab:
+		return f.move(-1), nil
+	case tea.KeyCtrlP:
+		if f.field == fieldDescription {
+			f.preview = !f.preview
+		}
+		return nil, nil
+	case tea.KeyCtrlE:
+		description := f.description.Value()
+		return func() tea.Msg { return EditDescriptionMsg{Description: description} }, nil
+	}
+
+	switch f.field {
+	case fieldTitle:
+		if msg.Type == tea.KeyEnter {
+			return f.move(1), nil
+		}
+		var cmd tea.Cmd
+		f.title, cmd = f.title.Update(msg)
+		return cmd, nil
+	case fieldDescription:
+		if f.preview {
+			return nil, nil
+		}
+		var cmd tea.Cmd
+		f.description, cmd = f.description.Update(msg)
+		return cmd, nil
+	case fieldReviewers:
+		return f.updateReviewers(msg), nil
+	case fieldSource:
+		return f.updateBranch(&f.source, msg), nil
+	case fieldTemplate:
+		return f.updateTemplate(msg), nil
+	case fieldCloseSource:
+		return f.updateCloseSource(msg), nil
+	default:
+		return f.updateBranch(&f.destination, msg), nil
+	}
+}
+
+func (f *EditForm) updateReviewers(msg tea.KeyMsg) tea.Cmd {
+	suggestions := f.reviewerSuggestions()
+	switch msg.Type {
+	case tea.KeyUp:
+		f.suggestion = max(f.suggestion-1, 0)
+		return nil
+	case tea.KeyDown:
+		f.suggestion = max(min(f.suggestion+1, len(suggestions)-1), 0)
+		return nil
+	case tea.KeyEnter:
+		if f.suggestion < len(suggestions) {
+			f.reviewers = append(f.reviewers, suggestions[f.suggestion])
+			f.reviewer.Reset()
+			f.suggestion = 0
+		}
+		return nil
+	case tea.KeyBackspace:
+		if f.reviewer.Value() == "" && len(f.reviewers) > 0 {
+			f.reviewers = f.reviewers[:len(f.reviewers)-1]
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.reviewer, cmd = f.reviewer.Update(msg)
+	f.suggestion = 0
+	return cmd
+}
+
+// updateBranch handles typing a branch name, completed from the branches of the repository
+func (f *EditForm) updateBranch(input *textinput.Model, msg tea.KeyMsg) tea.Cmd {
+	suggestions := f.branchSuggestions(input.Value())
+	switch msg.Type {
+	case tea.KeyUp:
+		f.suggestion = max(f.suggestion-1, 0)
+		return nil
+	case tea.KeyDown:
+		f.suggestion = max(min(f.suggestion+1, len(suggestions)-1), 0)
+		return nil
+	case tea.KeyEnter:
+		if f.suggestion < len(suggestions) {
+			input.SetValue(suggestions[f.suggestion])
+			input.CursorEnd()
+			f.suggestion = 0
+			return nil
+		}
+		if f.Creating() {
+			return f.move(1)
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	f.suggestion = 0
+	return cmd
+}
+
+// updateTemplate cycles through the templates, the last choice being none
+func (f *EditForm) updateTemplate(msg tea.KeyMsg) tea.Cmd {
+	choices := len(f.data.Templates) + 1
+	switch msg.Type {
+	case tea.KeyLeft, tea.KeyUp:
+		f.template = (f.template + choices - 1) % choices
+	case tea.KeyRight, tea.KeyDown, tea.KeySpace:
+		f.template = (f.template + 1) % choices
+	case tea.KeyEnter:
+		return f.move(1)
+	default:
+		return nil
+	}
+	f.applyTemplate()
+	return nil
+}
+
+// updateCloseSource toggles closing the source branch once the new PR is merged
+func (f *EditForm) updateCloseSource(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeySpace:
+		f.closeSource = !f.closeSource
+	case tea.KeyEnter:
+		return f.move(1)
+	}
+	return nil
+}
+
+// reviewerSuggestions lists the people matching the typed text, leaving out
+// the author and the reviewers already added
+func (f *EditForm) reviewerSuggestions() []Person {
+	query := strings.ToLower(strings.TrimSpace(f.reviewer.Value()))
+	if query == "" {
+		return nil
+	}
+
+	var matches []Person
+	for _, p := range f.data.People {
+		if p.UUID == f.data.AuthorUUID || f.hasReviewer(p.UUID) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(p.Name), query) || strings.Contains(strings.ToLower(p.Nickname), query) {
+			matches = append(matches, p)
+			if len(matches) == maxSuggestions {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func (f *EditForm) hasReviewer(uuid string) bool {
+	for _, r := range f.reviewers {
+		if r.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// branchSuggestions lists the branches matching the typed text
+func (f *EditForm) branchSuggestions(typed string) []string {
+	value := strings.TrimSpace(typed)
+	query := strings.ToLower(value)
+
+	var matches []string
+	for _, b := range f.data.Branches {
+		// Nothing to complete once a branch is typed in full
+		if b == value {
+			return nil
+		}
+		if strings.Contains(strings.ToLower(b), query) && len(matches) < maxSuggestions {
+			matches = append(matches, b)
+		}
+	}
+	return matches
+}
+
+// draft returns the edited PR, or nil with a notice when it cannot be saved
+func (f *EditForm) draft() *PRDraft {
+	title := strings.TrimSpace(f.title.Value())
+	destination := strings.TrimSpace(f.destination.Value())
+	source := strings.TrimSpace(f.source.Value())
+	switch {
+	case f.Creating() && source == "":
+		f.notice = "The source branch cannot be empty"
+		return nil
+	case title == "":
+		f.notice = "The title cannot be empty"
+		return nil
+	case destination == "":
+		f.notice = "The destination branch cannot be empty"
+		return nil
+	case f.Creating() && source == destination:
+		f.notice = "The source and destination branches must differ"
+		return nil
+	}
+
+	draft := &PRDraft{
+		Title:       title,
+		Description: f.description.Value(),
+		Reviewers:   f.reviewers,
+		Destination: destination,
+		Source:      source,
+		UpdatedAt:   f.data.UpdatedAt,
+	}
+	if f.Creating() {
+		draft.CloseSourceBranch = f.closeSource
+	}
+	if f.overwrite {
+		draft.UpdatedAt = time.Time{}
+	}
+
+	f.Saving = true
+	f.notice = ""
+	return draft
+}
+
+func (f *EditForm) View() string {
+	width := f.contentWidth()
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+
+	heading := "New PR in " + f.Repo
+	failure := "Failed to load the repository: "
+	if !f.Creating() {
+		heading = fmt.Sprintf("Edit #%d %s", f.PR.ID, f.PR.Title)
+		failure = "Failed to load the PR: "
+	}
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(ansi.Truncate(heading, width, "…")), "")
+
+	switch {
+	case f.Loading:
+		lines = append(lines, "Loading...")
+	case f.err != nil:
+		lines = append(lines, ansi.Wrap(failure+f.err.Error(), width, ""))
+	default:
+		for i, field := range f.fields() {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, f.fieldLines(field)...)
+		}
+	}
+
+	lines = append(lines, "")
+	switch {
+	case f.Saving:
+		lines = append(lines, textStyle.Render("Saving..."))
+	case f.notice != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(theme.Declined).Render(ansi.Wrap(f.notice, width, "")))
+	}
+
+	content := strings.Join(lines, "\n")
+	// Keep the hints on the bottom line
+	gap := max(f.Height-2-lipgloss.Height(content)-1, 0)
+	content += strings.Repeat("\n", gap) + "\n" + textStyle.Render(ansi.Truncate(editHints, width, "…"))
+
+	return lipgloss.NewStyle().
+		Width(f.Width-2).
+		Height(f.Height-2).
+		MaxHeight(f.Height).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(0, 2).
+		Render(content)
+}
+
+// fieldLines renders a field under its label, with its suggestions when focused
+func (f *EditForm) fieldLines(field editField) []string {
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	focused := field == f.field
+
+	label := func(name string) string {
+		if focused {
+			return lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render("▸ " + name)
+		}
+		return lipgloss.NewStyle().Bold(true).Render("  " + name)
+	}
+
+	switch field {
+	case fieldTitle:
+		return []string{label("Title"), f.title.View()}
+
+	case fieldDescription:
+		if f.preview {
+			return append([]string{label("Description (preview)")}, f.previewLines()...)
+		}
+		return []string{label("Description"), f.description.View()}
+
+	case fieldReviewers:
+		names := make([]string, len(f.reviewers))
+		for i, r := range f.reviewers {
+			names[i] = r.Name
+		}
+		reviewers := textStyle.Render(strings.Join(names, ", "))
+		if len(names) > 0 {
+			reviewers += "  "
+		}
+		lines := []string{label("Reviewers"), ansi.Truncate(reviewers+f.reviewer.View(), f.contentWidth(), "…")}
+		if focused {
+			suggestions := f.reviewerSuggestions()
+			items := make([]string, len(suggestions))
+			for i, p := range suggestions {
+				items[i] = p.Name
+				if p.Nickname != "" && p.Nickname != p.Name {
+					items[i] += " (" + p.Nickname + ")"
+				}
+			}
+			lines = append(lines, f.suggestionLines(items)...)
+		}
+		return lines
+
+	case fieldSource:
+		lines := []string{label("Source branch"), f.source.View()}
+		if focused {
+			lines = append(lines, f.suggestionLines(f.branchSuggestions(f.source.Value()))...)
+		}
+		return lines
+
+	case fieldTemplate:
+		name := "none"
+		if f.template < len(f.data.Templates) {
+			name = f.data.Templates[f.template].Name
+		}
+		choice := "◂ " + name + " ▸"
+		if focused {
+			choice = theme.SelectedStyle().Render(choice)
+		}
+		count := textStyle.Render(fmt.Sprintf("  %d of %d", f.template+1, len(f.data.Templates)+1))
+		return []string{label("Template"), ansi.Truncate(choice+count, f.contentWidth(), "…")}
+
+	case fieldCloseSource:
+		box := "[ ]"
+		if f.closeSource {
+			box = "[x]"
+		}
+		choice := box + " close the source branch when merged"
+		if focused {
+			choice = theme.SelectedStyle().Render(box) + textStyle.Render(strings.TrimPrefix(choice, box))
+		} else {
+			choice = textStyle.Render(choice)
+		}
+		return []string{label("Source branch after merge"), ansi.Truncate(choice, f.contentWidth(), "…")}
+
+	default:
+		lines := []string{label("Destination branch"), f.destination.View()}
+		if focused {
+			lines = append(lines, f.suggestionLines(f.branchSuggestions(f.destination.Value()))...)
+		}
+		return lines
+	}
+}
+
+// previewLines renders the description as markdown, cut to the editor height
+func (f *EditForm) previewLines() []string {
+	rendered := f.description.Value()
+	if renderer, err := markdownRenderer(f.contentWidth()); err == nil {
+		if out, err := renderer.Render(rendered); err == nil {
+			rendered = strings.Trim(out, "\n")
+		}
+	}
+
+	lines := strings.Split(rendered, "\n")
+	height := f.descriptionHeight()
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// suggestionLines lists autocomplete suggestions, highlighting the selected one
+func (f *EditForm) suggestionLines(items []string) []string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		item = truncateString(item, max(f.contentWidth()-2, 1))
+		if i == f.suggestion {
+			lines[i] = "  " + theme.SelectedStyle().Render(item)
+			continue
+		}
+		lines[i] = "  " + lipgloss.NewStyle().Foreground(theme.Text).Render(item)
+	}
+	return lines
+}
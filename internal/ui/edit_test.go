@@ -0,0 +1,98 @@
+package ui
+
+import "testing"
+
+func TestApplyTemplate(t *testing.T) {
+	templates := []Template{
+		{Name: "Feature", Body: "## {{jira}}\n\n{{commits}}"},
+		{Name: "Short", Body: "Into {{destination}}"},
+	}
+
+	tests := []struct {
+		name string
+		// edit changes the form between loading and the commits arriving
+		edit            func(f *EditForm)
+		commits         []string
+		wantTitle       string
+		wantDescription string
+	}{
+		{
+			"title from the branch",
+			func(f *EditForm) {}, []string{"one", "two"},
+			"ABC-12 fix login", "## ABC-12\n\n- one\n- two",
+		},
+		{
+			"title from a single commit",
+			func(f *EditForm) {}, []string{"Fix the login form"},
+			"Fix the login form", "## ABC-12\n\n- Fix the login form",
+		},
+		{
+			"typed title is kept",
+			func(f *EditForm) { f.title.SetValue("My title") }, []string{"one"},
+			"My title", "## ABC-12\n\n- one",
+		},
+		{
+			"typed description is kept",
+			func(f *EditForm) { f.description.SetValue("Mine") }, []string{"one"},
+			"one", "Mine",
+		},
+		{
+			"another template",
+			func(f *EditForm) { f.template = 1 }, nil,
+			"ABC-12 fix login", "Into main",
+		},
+		{
+			"no template",
+			func(f *EditForm) { f.template = len(templates) }, nil,
+			"ABC-12 fix login", "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewEditForm()
+			f.OpenCreate("app")
+			f.Load(EditData{Destination: "main", Templates: templates}, nil)
+
+			if got := f.description.Value(); got != "## \n\n" {
+				t.Errorf("description before picking branches = %q", got)
+			}
+
+			f.source.SetValue("feature/ABC-12-fix-login")
+			tt.edit(f)
+			f.SetCommits("feature/ABC-12-fix-login", "main", tt.commits)
+
+			if got := f.title.Value(); got != tt.wantTitle {
+				t.Errorf("title = %q, want %q", got, tt.wantTitle)
+			}
+			if got := f.description.Value(); got != tt.wantDescription {
+				t.Errorf("description = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateFollowsBranches(t *testing.T) {
+	f := NewEditForm()
+	f.OpenCreate("app")
+	f.Load(EditData{Destination: "main"}, nil)
+
+	f.source.SetValue("ABC-1-first")
+	f.SetCommits("ABC-1-first", "main", nil)
+	if got := f.title.Value(); got != "ABC-1 first" {
+		t.Fatalf("title = %q, want ABC-1 first", got)
+	}
+
+	// The suggested title follows another branch until it is edited
+	f.source.SetValue("ABC-2-second")
+	f.SetCommits("ABC-2-second", "main", nil)
+	if got := f.title.Value(); got != "ABC-2 second" {
+		t.Errorf("title = %q, want ABC-2 second", got)
+	}
+
+	// Commits of branches picked before the current ones are ignored
+	f.SetCommits("ABC-1-first", "main", []string{"stale"})
+	if got := f.title.Value(); got != "ABC-2 second" {
+		t.Errorf("title after stale commits = %q, want ABC-2 second", got)
+	}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HelpView renders the short help footer and the full help overlay
+// for whichever pane currently has focus
+type HelpView struct {
+	Keys      *KeyMap
+	Pane      Pane
+	Width     int
+	Height    int
+	Visible   bool
+	Searching bool
+	help      help.Model
+	search    textinput.Model
+}
+
+func NewHelpView(keys *KeyMap) *HelpView {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "filter bindings"
+	search.Cursor.SetMode(cursor.CursorStatic)
+
+	h := help.New()
+	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(theme.Accent)
+	h.Styles.ShortDesc = lipgloss.NewStyle().Foreground(theme.Text)
+	h.Styles.ShortSeparator = lipgloss.NewStyle().Foreground(theme.Border)
+	h.Styles.FullKey = lipgloss.NewStyle().Foreground(theme.Accent)
+	h.Styles.FullDesc = lipgloss.NewStyle().Foreground(theme.Text)
+	h.Styles.FullSeparator = lipgloss.NewStyle().Foreground(theme.Border)
+
+	return &HelpView{
+		Keys:   keys,
+		help:   h,
+		search: search,
+	}
+}
+
+// Open shows the overlay with the bindings valid for the given pane
+func (h *HelpView) Open(pane Pane) {
+	h.Pane = pane
+	h.Visible = true
+	h.Searching = false
+	h.search.Reset()
+	h.search.Blur()
+}
+
+func (h *HelpView) Close() {
+	h.Visible = false
+	h.Searching = false
+	h.search.Blur()
+}
+
+// Update handles key presses while the overlay is visible
+func (h *HelpView) Update(msg tea.KeyMsg) tea.Cmd {
+	if h.Searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			h.Searching = false
+			h.search.Blur()
+			return nil
+		case tea.KeyEsc:
+			h.Searching = false
+			h.search.Reset()
+			h.search.Blur()
+			return nil
+		}
+
+		var cmd tea.Cmd
+		h.search, cmd = h.search.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, h.Keys.Search):
+		h.Searching = true
+		return h.search.Focus()
+	case key.Matches(msg, h.Keys.Help), msg.Type == tea.KeyEsc:
+		h.Close()
+	}
+
+	return nil
+}
+
+// ShortView renders the one-line hint shown beneath the panes, cut to width
+func (h *HelpView) ShortView(pane Pane, width int) string {
+	h.help.Width = width
+	return h.help.ShortHelpView(h.Keys.ShortHelp(pane))
+}
+
+// View renders the full help overlay centered in the terminal
+func (h *HelpView) View() string {
+	query := strings.ToLower(strings.TrimSpace(h.search.Value()))
+
+	var columns []string
+	for _, group := range h.Keys.Groups(h.Pane) {
+		bindings := filterBindings(group.Bindings, query)
+		if len(bindings) == 0 {
+			continue
+		}
+
+		title := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render(group.Title)
+		body := h.help.FullHelpView([][]key.Binding{bindings})
+		columns = append(columns, lipgloss.NewStyle().PaddingRight(4).Render(title+"\n"+body))
+	}
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	output.WriteString(titleStyle.Render("Help - " + h.Pane.String()))
+	output.WriteString("\n\n")
+
+	if len(columns) == 0 {
+		output.WriteString("No matching bindings")
+	} else {
+		output.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+	}
+	output.WriteString("\n\n")
+
+	if h.Searching || h.search.Value() != "" {
+		output.WriteString(h.search.View())
+	} else {
+		output.WriteString(h.help.ShortHelpView(h.Keys.ShortHelp(PaneHelp)))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Render(output.String())
+
+	return lipgloss.Place(h.Width, h.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// filterBindings keeps the bindings whose keys or description contain the query
+func filterBindings(bindings []key.Binding, query string) []key.Binding {
+	if query == "" {
+		return bindings
+	}
+
+	var filtered []key.Binding
+	for _, b := range bindings {
+		text := strings.ToLower(b.Help().Key + " " + b.Help().Desc + " " + strings.Join(b.Keys(), " "))
+		if strings.Contains(text, query) {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
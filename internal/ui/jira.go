@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jiraKeyPattern matches Jira issue keys such as ABC-123
+var jiraKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// SetJiraKeyPattern replaces the pattern issue keys are detected with, an
+// empty pattern keeping the default
+func SetJiraKeyPattern(pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid jira key_pattern %q: %w", pattern, err)
+	}
+	jiraKeyPattern = re
+	return nil
+}
+
+// JiraKey returns the first Jira issue key in text, or ""
+func JiraKey(text string) string {
+	return jiraKeyPattern.FindString(text)
+}
+
+// JiraKeys lists the distinct Jira issue keys in the texts, in order of appearance
+func JiraKeys(texts ...string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, key := range jiraKeyPattern.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// JiraKeys lists the issues a PR is for, from its title and source branch
+func (pr PR) JiraKeys() []string {
+	return JiraKeys(pr.Title, pr.SourceBranch)
+}
+
+// JiraIssueURL returns the page of an issue under baseURL
+func JiraIssueURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/browse/" + key
+}
+
+// JiraLinks lists the Jira issues mentioned in text, linked under baseURL.
+// Nothing is returned when no Jira site is configured
+func JiraLinks(source, text, baseURL string) []Link {
+	if baseURL == "" {
+		return nil
+	}
+
+	var links []Link
+	for _, key := range jiraKeyPattern.FindAllString(text, -1) {
+		links = append(links, Link{
+			Source: source,
+			Label:  key,
+			URL:    JiraIssueURL(baseURL, key),
+		})
+	}
+	return links
+}
+
+// IssueCategory groups issue statuses for coloring
+type IssueCategory int
+
+const (
+	IssueTodo IssueCategory = iota
+	IssueInProgress
+	IssueDone
+)
+
+func (c IssueCategory) color() lipgloss.TerminalColor {
+	switch c {
+	case IssueInProgress:
+		return theme.Accent
+	case IssueDone:
+		return theme.Open
+	default:
+		return theme.Text
+	}
+}
+
+// Issue is a tracker issue mentioned by a PR
+type Issue struct {
+	Key      string
+	Summary  string
+	Status   string
+	Category IssueCategory
+}
+
+// issueResult is an issue fetched from the tracker, or why it could not be
+type issueResult struct {
+	issue *Issue
+	err   error
+}
+
+// PendingIssues lists the issues of the shown PR that must be fetched, and
+// records them as requested so each is only reported once
+func (p *PRDetail) PendingIssues() []string {
+	if p.PR == nil {
+		return nil
+	}
+
+	var pending []string
+	for _, key := range p.PR.JiraKeys() {
+		if !p.requested["issue/"+key] {
+			p.requested["issue/"+key] = true
+			pending = append(pending, key)
+		}
+	}
+	return pending
+}
+
+// SetIssue stores an issue fetched from the tracker
+func (p *PRDetail) SetIssue(key string, issue *Issue, err error) {
+	p.issues[key] = issueResult{issue: issue, err: err}
+	p.generation++
+	p.sync()
+}
+
+// issuesLoaded counts the issues of the shown PR fetched so far, so the
+// overview is rendered again as they arrive
+func (p *PRDetail) issuesLoaded() int {
+	loaded := 0
+	for _, key := range p.PR.JiraKeys() {
+		if _, ok := p.issues[key]; ok {
+			loaded++
+		}
+	}
+	return loaded
+}
+
+// renderIssues lists the issues of the PR, linked to the Jira site when
+// configured and with their summary and status once fetched
+func (p *PRDetail) renderIssues() []string {
+	linkStyle := lipgloss.NewStyle().Foreground(theme.Accent).Underline(true)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+
+	var lines []string
+	for _, key := range p.PR.JiraKeys() {
+		badge := key
+		if p.JiraURL != "" {
+			badge = Hyperlink(JiraIssueURL(p.JiraURL, key), linkStyle.Render(key))
+		}
+
+		result, ok := p.issues[key]
+		switch {
+		case !ok:
+		case result.err != nil:
+			badge += textStyle.Render("  unavailable: " + result.err.Error())
+		default:
+			status := lipgloss.NewStyle().Foreground(result.issue.Category.color()).Render("[" + result.issue.Status + "]")
+			badge += "  " + status + " " + result.issue.Summary
+		}
+		lines = append(lines, "  "+badge)
+	}
+	return lines
+}
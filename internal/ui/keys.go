@@ -0,0 +1,512 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Pane identifies a focusable area of the TUI, used to scope keybindings
+type Pane int
+
+const (
+	PanePRList Pane = iota
+	PaneDetail
+	PaneRepoList
+	PaneHelp
+	PaneLinks
+	PaneMenu
+	PaneURL
+	PaneDiff
+	PaneMerge
+)
+
+func (p Pane) String() string {
+	switch p {
+	case PanePRList:
+		return "PR list"
+	case PaneDetail:
+		return "detail"
+	case PaneRepoList:
+		return "repo list"
+	case PaneHelp:
+		return "help"
+	case PaneLinks:
+		return "links"
+	case PaneMenu:
+		return "menu"
+	case PaneURL:
+		return "url"
+	case PaneDiff:
+		return "diff"
+	case PaneMerge:
+		return "merge"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePane returns the pane with the given String name
+func ParsePane(name string) (Pane, bool) {
+	for _, p := range []Pane{PanePRList, PaneDetail, PaneRepoList, PaneHelp, PaneLinks, PaneMenu, PaneURL, PaneDiff, PaneMerge} {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// Binding categories, in the order they are shown in the help overlay
+const (
+	categoryNavigation = "Navigation"
+	categoryActions    = "Actions"
+	categoryFocus      = "Panes"
+	categoryGeneral    = "General"
+)
+
+var categoryOrder = []string{categoryNavigation, categoryActions, categoryFocus, categoryGeneral}
+
+// KeyMap holds every keybinding used by the TUI
+type KeyMap struct {
+	Quit          key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Enter         key.Binding
+	FocusPRList   key.Binding
+	FocusDetail   key.Binding
+	FocusRepoList key.Binding
+	CycleLeftPane key.Binding
+	Refresh       key.Binding
+	HalfPageUp    key.Binding
+	HalfPageDown  key.Binding
+	Help          key.Binding
+	Search        key.Binding
+	SortNext      key.Binding
+	SortReverse   key.Binding
+	CycleRepoRole key.Binding
+	CycleProject  key.Binding
+	TogglePin     key.Binding
+	CycleLayout   key.Binding
+	ToggleZoom    key.Binding
+	GrowSplit     key.Binding
+	ShrinkSplit   key.Binding
+	NextMatch     key.Binding
+	PrevMatch     key.Binding
+	OpenLinks     key.Binding
+	CopyLink      key.Binding
+	Yank          key.Binding
+	NextTab       key.Binding
+	PrevTab       key.Binding
+	Collapse      key.Binding
+	ToggleTask    key.Binding
+	NewTask       key.Binding
+	CommentTask   key.Binding
+	Merge         key.Binding
+	Edit          key.Binding
+	Comment       key.Binding
+	Decline       key.Binding
+	Create        key.Binding
+	Confirm       key.Binding
+	Override      key.Binding
+	Close         key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q/esc", "quit"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+		FocusPRList: key.NewBinding(
+			key.WithKeys("1"),
+			key.WithHelp("1", "focus PR list"),
+		),
+		FocusDetail: key.NewBinding(
+			key.WithKeys("2"),
+			key.WithHelp("2", "focus detail"),
+		),
+		FocusRepoList: key.NewBinding(
+			key.WithKeys("3"),
+			key.WithHelp("3", "focus repo list"),
+		),
+		CycleLeftPane: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "cycle PR/Repo"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		HalfPageUp: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "half page up"),
+		),
+		HalfPageDown: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "half page down"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		SortNext: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort by next column"),
+		),
+		SortReverse: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort"),
+		),
+		CycleRepoRole: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "cycle repo role"),
+		),
+		CycleProject: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "filter by project"),
+		),
+		TogglePin: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "pin/unpin repo"),
+		),
+		CycleLayout: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "cycle layout"),
+		),
+		ToggleZoom: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "zoom detail"),
+		),
+		GrowSplit: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "grow lists"),
+		),
+		ShrinkSplit: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "shrink lists"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		OpenLinks: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "links"),
+		),
+		CopyLink: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy link"),
+		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "yank"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next tab"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous tab"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "collapse events"),
+		),
+		ToggleTask: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "resolve/reopen task"),
+		),
+		NewTask: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add task"),
+		),
+		CommentTask: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "task from comment"),
+		),
+		Merge: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "merge"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "edit PR"),
+		),
+		Comment: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "comment"),
+		),
+		Decline: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "decline PR"),
+		),
+		Create: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "create PR"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		Override: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "merge anyway"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
+
+// namedBinding ties a binding to the action name used in the config file
+type namedBinding struct {
+	name     string
+	category string
+	binding  *key.Binding
+}
+
+// actions lists every configurable binding by its config file name
+func (k *KeyMap) actions() []namedBinding {
+	return []namedBinding{
+		{"quit", categoryGeneral, &k.Quit},
+		{"up", categoryNavigation, &k.Up},
+		{"down", categoryNavigation, &k.Down},
+		{"enter", categoryActions, &k.Enter},
+		{"focus_pr_list", categoryFocus, &k.FocusPRList},
+		{"focus_detail", categoryFocus, &k.FocusDetail},
+		{"focus_repo_list", categoryFocus, &k.FocusRepoList},
+		{"cycle_left_pane", categoryFocus, &k.CycleLeftPane},
+		{"refresh", categoryActions, &k.Refresh},
+		{"half_page_up", categoryNavigation, &k.HalfPageUp},
+		{"half_page_down", categoryNavigation, &k.HalfPageDown},
+		{"help", categoryGeneral, &k.Help},
+		{"search", categoryGeneral, &k.Search},
+		{"sort_next", categoryActions, &k.SortNext},
+		{"sort_reverse", categoryActions, &k.SortReverse},
+		{"cycle_repo_role", categoryActions, &k.CycleRepoRole},
+		{"cycle_project", categoryActions, &k.CycleProject},
+		{"toggle_pin", categoryActions, &k.TogglePin},
+		{"cycle_layout", categoryFocus, &k.CycleLayout},
+		{"toggle_zoom", categoryFocus, &k.ToggleZoom},
+		{"grow_split", categoryFocus, &k.GrowSplit},
+		{"shrink_split", categoryFocus, &k.ShrinkSplit},
+		{"next_match", categoryNavigation, &k.NextMatch},
+		{"prev_match", categoryNavigation, &k.PrevMatch},
+		{"open_links", categoryActions, &k.OpenLinks},
+		{"copy_link", categoryActions, &k.CopyLink},
+		{"yank", categoryActions, &k.Yank},
+		{"next_tab", categoryNavigation, &k.NextTab},
+		{"prev_tab", categoryNavigation, &k.PrevTab},
+		{"collapse", categoryActions, &k.Collapse},
+		{"toggle_task", categoryActions, &k.ToggleTask},
+		{"new_task", categoryActions, &k.NewTask},
+		{"comment_task", categoryActions, &k.CommentTask},
+		{"merge", categoryActions, &k.Merge},
+		{"edit", categoryActions, &k.Edit},
+		{"comment", categoryActions, &k.Comment},
+		{"decline", categoryActions, &k.Decline},
+		{"create", categoryActions, &k.Create},
+		{"confirm", categoryActions, &k.Confirm},
+		{"override", categoryActions, &k.Override},
+		{"close", categoryGeneral, &k.Close},
+	}
+}
+
+// global returns the bindings that are active regardless of the focused pane
+func (k *KeyMap) global() []namedBinding {
+	return k.pick("quit", "help", "refresh", "focus_pr_list", "focus_detail", "focus_repo_list", "cycle_left_pane",
+		"cycle_layout", "toggle_zoom", "grow_split", "shrink_split")
+}
+
+// detailActions are the bindings of the detail pane besides the global ones
+var detailActions = []string{"up", "down", "enter", "half_page_up", "half_page_down", "search", "next_match", "prev_match",
+	"open_links", "yank", "next_tab", "prev_tab", "collapse", "toggle_task", "new_task", "comment_task", "merge", "edit", "comment", "decline"}
+
+// scopes returns, per pane, the bindings that may be matched while it is focused
+func (k *KeyMap) scopes() map[Pane][]namedBinding {
+	return map[Pane][]namedBinding{
+		PanePRList:   append(k.pick("up", "down", "enter", "sort_next", "sort_reverse", "open_links", "yank", "merge", "edit", "comment", "decline", "create"), k.global()...),
+		PaneRepoList: append(k.pick("up", "down", "enter", "sort_next", "cycle_repo_role", "cycle_project", "toggle_pin", "create"), k.global()...),
+		PaneDetail:   append(k.pick(detailActions...), k.global()...),
+		PaneHelp:     k.pick("help", "search"),
+		PaneLinks:    k.pick("up", "down", "enter", "copy_link", "close"),
+		PaneMenu:     k.pick("up", "down", "enter", "close"),
+		PaneURL:      k.pick("copy_link", "close"),
+		PaneDiff:     k.pick("up", "down", "half_page_up", "half_page_down", "close"),
+		PaneMerge:    k.pick("confirm", "override", "close"),
+	}
+}
+
+func (k *KeyMap) pick(names ...string) []namedBinding {
+	actions := k.actions()
+
+	var picked []namedBinding
+	for _, name := range names {
+		for _, action := range actions {
+			if action.name == name {
+				picked = append(picked, action)
+			}
+		}
+	}
+	return picked
+}
+
+// BindingGroup is a titled set of bindings shown together in the help overlay
+type BindingGroup struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// Groups returns the bindings valid in a pane, grouped by category
+func (k *KeyMap) Groups(pane Pane) []BindingGroup {
+	scope := k.scopes()[pane]
+
+	var groups []BindingGroup
+	for _, category := range categoryOrder {
+		group := BindingGroup{Title: category}
+		for _, action := range scope {
+			if action.category == category {
+				group.Bindings = append(group.Bindings, *action.binding)
+			}
+		}
+		if len(group.Bindings) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ShortHelp returns the handful of bindings hinted in the footer for a pane
+func (k *KeyMap) ShortHelp(pane Pane) []key.Binding {
+	switch pane {
+	case PanePRList:
+		return []key.Binding{k.Up, k.Down, k.Enter, k.SortNext, k.OpenLinks, k.Yank, k.Refresh, k.Help, k.Quit}
+	case PaneRepoList:
+		return []key.Binding{k.Up, k.Down, k.Enter, k.TogglePin, k.SortNext, k.CycleProject, k.CycleRepoRole, k.Help, k.Quit}
+	case PaneDetail:
+		return []key.Binding{k.Up, k.Down, k.HalfPageUp, k.HalfPageDown, k.NextTab, k.Search, k.Yank, k.ToggleZoom, k.Help, k.Quit}
+	case PaneHelp:
+		return []key.Binding{k.Search, k.Help}
+	case PaneLinks:
+		return []key.Binding{k.Up, k.Down, k.Enter, k.CopyLink, k.Close}
+	case PaneMenu:
+		return []key.Binding{k.Up, k.Down, k.Enter, k.Close}
+	case PaneURL:
+		return []key.Binding{k.CopyLink, k.Close}
+	case PaneDiff:
+		return []key.Binding{k.Up, k.Down, k.HalfPageUp, k.HalfPageDown, k.Close}
+	case PaneMerge:
+		return []key.Binding{k.Confirm, k.Override, k.Close}
+	default:
+		return []key.Binding{k.Help, k.Quit}
+	}
+}
+
+// Apply overrides bindings with the keys configured for each action name
+func (k *KeyMap) Apply(overrides map[string][]string) error {
+	actions := k.actions()
+
+	for name, keys := range overrides {
+		var target *key.Binding
+		for _, action := range actions {
+			if action.name == name {
+				target = action.binding
+				break
+			}
+		}
+
+		if target == nil {
+			return fmt.Errorf("unknown key action %q", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("key action %q has no keys", name)
+		}
+
+		target.SetKeys(keys...)
+		target.SetHelp(formatKeys(keys), target.Help().Desc)
+	}
+
+	return nil
+}
+
+// Validate reports keys bound to more than one action within the same pane
+func (k *KeyMap) Validate() error {
+	var conflicts []string
+
+	scopes := k.scopes()
+	panes := make([]Pane, 0, len(scopes))
+	for pane := range scopes {
+		panes = append(panes, pane)
+	}
+	sort.Slice(panes, func(i, j int) bool { return panes[i] < panes[j] })
+
+	seen := make(map[string]bool)
+	for _, pane := range panes {
+		owners := make(map[string]string)
+		for _, action := range scopes[pane] {
+			for _, keyName := range action.binding.Keys() {
+				owner, taken := owners[keyName]
+				if !taken {
+					owners[keyName] = action.name
+					continue
+				}
+
+				conflict := fmt.Sprintf("%q is bound to both %s and %s", keyName, owner, action.name)
+				if !seen[conflict] {
+					seen[conflict] = true
+					conflicts = append(conflicts, fmt.Sprintf("%s (in %s)", conflict, pane))
+				}
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("conflicting keybindings: %s", strings.Join(conflicts, "; "))
+	}
+
+	return nil
+}
+
+// formatKeys renders a list of keys the way they are shown in help text
+func formatKeys(keys []string) string {
+	symbols := map[string]string{
+		"up":    "↑",
+		"down":  "↓",
+		"left":  "←",
+		"right": "→",
+	}
+
+	display := make([]string, len(keys))
+	for i, k := range keys {
+		if symbol, ok := symbols[k]; ok {
+			k = symbol
+		}
+		display[i] = k
+	}
+
+	return strings.Join(display, "/")
+}
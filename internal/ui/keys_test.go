@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestKeyMapValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		// wantConflicts are the reported conflicts, none meaning valid
+		wantConflicts []string
+	}{
+		{"defaults", nil, nil},
+		{
+			"same key in different panes",
+			map[string][]string{"cycle_repo_role": {"D"}},
+			nil,
+		},
+		{
+			"same key within a pane",
+			map[string][]string{"sort_next": {"k"}},
+			[]string{`"k" is bound to both up and sort_next (in PR list)`},
+		},
+		{
+			"pane action against a global one",
+			map[string][]string{"collapse": {"q"}},
+			[]string{`"q" is bound to both collapse and quit (in detail)`},
+		},
+		{
+			"overlay pane",
+			map[string][]string{"confirm": {"y", "esc"}},
+			[]string{`"esc" is bound to both confirm and close (in merge)`},
+		},
+		{
+			"several conflicts",
+			map[string][]string{"sort_next": {"k"}, "confirm": {"esc"}},
+			[]string{
+				`"k" is bound to both up and sort_next (in PR list)`,
+				`"esc" is bound to both confirm and close (in merge)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := DefaultKeyMap()
+			if err := keys.Apply(tt.overrides); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			err := keys.Validate()
+			if len(tt.wantConflicts) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want no conflicts", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() succeeded, want %v", tt.wantConflicts)
+			}
+
+			conflicts := strings.Split(strings.TrimPrefix(err.Error(), "conflicting keybindings: "), "; ")
+			if !slices.Equal(conflicts, tt.wantConflicts) {
+				t.Errorf("Validate() conflicts = %q, want %q", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestKeyMapApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{"known action", map[string][]string{"up": {"up", "w"}}, ""},
+		{"unknown action", map[string][]string{"jump": {"g"}}, `unknown key action "jump"`},
+		{"misspelled action", map[string][]string{"sort-next": {"o"}}, `unknown key action "sort-next"`},
+		{"no keys", map[string][]string{"up": {}}, `key action "up" has no keys`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := DefaultKeyMap()
+			err := keys.Apply(tt.overrides)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Apply() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() failed: %v", err)
+			}
+
+			if got := keys.Up.Keys(); !slices.Equal(got, []string{"up", "w"}) {
+				t.Errorf("up keys = %v, want [up w]", got)
+			}
+			// The help keeps the description and shows the new keys
+			if help := keys.Up.Help(); help.Key != "↑/w" || help.Desc != "up" {
+				t.Errorf("up help = %+v, want ↑/w up", help)
+			}
+		})
+	}
+}
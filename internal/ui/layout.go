@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LayoutMode is how the panes are arranged on screen
+type LayoutMode int
+
+const (
+	// LayoutAuto is side-by-side, switching to stacked on narrow terminals
+	LayoutAuto LayoutMode = iota
+	// LayoutSideBySide puts the PR and repo lists on the left and the detail on the right
+	LayoutSideBySide
+	// LayoutStacked puts one list above the detail, both at full width
+	LayoutStacked
+)
+
+var layoutModes = []LayoutMode{LayoutAuto, LayoutSideBySide, LayoutStacked}
+
+func (m LayoutMode) String() string {
+	switch m {
+	case LayoutSideBySide:
+		return "side-by-side"
+	case LayoutStacked:
+		return "stacked"
+	default:
+		return "auto"
+	}
+}
+
+// ParseLayoutMode resolves a layout mode name from the config file
+func ParseLayoutMode(name string) (LayoutMode, error) {
+	if name == "" {
+		return LayoutAuto, nil
+	}
+
+	names := make([]string, len(layoutModes))
+	for i, mode := range layoutModes {
+		if mode.String() == name {
+			return mode, nil
+		}
+		names[i] = mode.String()
+	}
+	return 0, fmt.Errorf("unknown layout %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Split bounds, in percent of the screen given to the lists
+const (
+	DefaultSplit = 50
+	minSplit     = 20
+	maxSplit     = 80
+	splitStep    = 5
+)
+
+// DefaultStackBelow is the terminal width under which the auto layout stacks the panes
+const DefaultStackBelow = 100
+
+// Rect is the screen area of a pane, borders included
+type Rect struct {
+	X, Y, W, H int
+}
+
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
+
+// Layout decides where each pane goes
+type Layout struct {
+	Mode LayoutMode
+	// Split is the percentage of the width (side-by-side) or height (stacked)
+	// given to the lists
+	Split int
+	// StackBelow is the width under which the auto layout stacks the panes
+	StackBelow int
+	// Zoom shows only the detail pane
+	Zoom bool
+}
+
+func NewLayout() Layout {
+	return Layout{Mode: LayoutAuto, Split: DefaultSplit, StackBelow: DefaultStackBelow}
+}
+
+// SetSplit sets the list share, clamped to the allowed range
+func (l *Layout) SetSplit(split int) {
+	l.Split = min(max(split, minSplit), maxSplit)
+}
+
+// Grow gives the lists more room
+func (l *Layout) Grow() {
+	l.SetSplit(l.Split + splitStep)
+}
+
+// Shrink gives the detail pane more room
+func (l *Layout) Shrink() {
+	l.SetSplit(l.Split - splitStep)
+}
+
+// CycleMode switches to the next layout mode
+func (l *Layout) CycleMode() {
+	l.Mode = layoutModes[(int(l.Mode)+1)%len(layoutModes)]
+}
+
+// Effective resolves the auto mode for a terminal width
+func (l Layout) Effective(width int) LayoutMode {
+	if l.Mode != LayoutAuto {
+		return l.Mode
+	}
+	if width < l.StackBelow {
+		return LayoutStacked
+	}
+	return LayoutSideBySide
+}
+
+// Arrange places the panes in a width x height area. In the stacked layout
+// only list, the PR or repo list, is shown above the detail. Panes left out
+// of the result are hidden
+func (l Layout) Arrange(width, height int, list Pane) map[Pane]Rect {
+	if l.Zoom {
+		return map[Pane]Rect{PaneDetail: {X: 0, Y: 0, W: width, H: height}}
+	}
+
+	if l.Effective(width) == LayoutStacked {
+		listHeight := height * l.Split / 100
+		return map[Pane]Rect{
+			list:       {X: 0, Y: 0, W: width, H: listHeight},
+			PaneDetail: {X: 0, Y: listHeight, W: width, H: height - listHeight},
+		}
+	}
+
+	listWidth := width * l.Split / 100
+	prHeight := height / 2
+	return map[Pane]Rect{
+		PanePRList:   {X: 0, Y: 0, W: listWidth, H: prHeight},
+		PaneRepoList: {X: 0, Y: prHeight, W: listWidth, H: height - prHeight},
+		PaneDetail:   {X: listWidth, Y: 0, W: width - listWidth, H: height},
+	}
+}
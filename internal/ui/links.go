@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Link is a URL found in a PR, along with where it was found
+type Link struct {
+	Source string
+	Label  string
+	URL    string
+}
+
+// hyperlinks enables OSC 8 hyperlinks, set with SetHyperlinks at startup
+var hyperlinks bool
+
+func SetHyperlinks(enabled bool) {
+	hyperlinks = enabled
+}
+
+// Hyperlink makes text a clickable link to url when hyperlinks are enabled
+func Hyperlink(url, text string) string {
+	if !hyperlinks || url == "" {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// urlPattern finds URLs in text, stopping at escape sequences in rendered output
+var urlPattern = regexp.MustCompile(`https?://[^\s<>()\[\]"'\x1b]+`)
+
+var osc8Pattern = regexp.MustCompile("\x1b]8;;([^\x1b]*)\x1b\\\\")
+
+// linkify turns every URL in rendered text into a hyperlink
+func linkify(text string) string {
+	if !hyperlinks {
+		return text
+	}
+	return urlPattern.ReplaceAllStringFunc(text, func(url string) string {
+		return Hyperlink(url, url)
+	})
+}
+
+// balanceHyperlinks closes hyperlinks at the end of each line and reopens them
+// on the next, so links split by wrapping do not spill into the pane border
+func balanceHyperlinks(lines []string) []string {
+	open := ""
+	for i, line := range lines {
+		prefix := ""
+		if open != "" {
+			prefix = "\x1b]8;;" + open + "\x1b\\"
+		}
+		for _, m := range osc8Pattern.FindAllStringSubmatch(line, -1) {
+			open = m[1]
+		}
+		suffix := ""
+		if open != "" {
+			suffix = "\x1b]8;;\x1b\\"
+		}
+		lines[i] = prefix + line + suffix
+	}
+	return lines
+}
+
+// ExtractLinks lists the URLs in text
+func ExtractLinks(source, text string) []Link {
+	var links []Link
+	for _, url := range urlPattern.FindAllString(text, -1) {
+		url = strings.TrimRight(url, ".,;:!?")
+		links = append(links, Link{Source: source, Label: url, URL: url})
+	}
+	return links
+}
+
+// UniqueLinks drops repeated URLs, keeping the first occurrence
+func UniqueLinks(links []Link) []Link {
+	seen := make(map[string]bool, len(links))
+	var unique []Link
+	for _, link := range links {
+		if !seen[link.URL] {
+			seen[link.URL] = true
+			unique = append(unique, link)
+		}
+	}
+	return unique
+}
+
+// LinkPicker is an overlay listing every link of a PR to open or copy one
+type LinkPicker struct {
+	Links   []Link
+	Cursor  int
+	Width   int
+	Height  int
+	Visible bool
+	Loading bool
+	// PRKey is the PR whose links are listed
+	PRKey string
+}
+
+func NewLinkPicker() *LinkPicker {
+	return &LinkPicker{}
+}
+
+// Open shows the picker while the links of a PR are being gathered
+func (l *LinkPicker) Open(prKey string) {
+	l.PRKey = prKey
+	l.Links = nil
+	l.Cursor = 0
+	l.Loading = true
+	l.Visible = true
+}
+
+func (l *LinkPicker) SetLinks(links []Link) {
+	l.Links = links
+	l.Cursor = 0
+	l.Loading = false
+}
+
+func (l *LinkPicker) Close() {
+	l.Visible = false
+}
+
+func (l *LinkPicker) MoveUp() {
+	if l.Cursor > 0 {
+		l.Cursor--
+	}
+}
+
+func (l *LinkPicker) MoveDown() {
+	if l.Cursor < len(l.Links)-1 {
+		l.Cursor++
+	}
+}
+
+func (l *LinkPicker) GetSelected() *Link {
+	if l.Cursor >= 0 && l.Cursor < len(l.Links) {
+		return &l.Links[l.Cursor]
+	}
+	return nil
+}
+
+// View renders the picker centered in the terminal
+func (l *LinkPicker) View(hints string) string {
+	boxWidth := max(min(l.Width-8, 100), 20)
+	visible := max(l.Height-10, 1)
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	output.WriteString(titleStyle.Render("Links"))
+	output.WriteString("\n\n")
+
+	switch {
+	case l.Loading:
+		output.WriteString("Gathering links...")
+	case len(l.Links) == 0:
+		output.WriteString("No links found")
+	default:
+		sourceWidth := 0
+		for _, link := range l.Links {
+			sourceWidth = max(sourceWidth, len(link.Source))
+		}
+
+		offset := scrollOffset(0, l.Cursor, visible)
+		end := min(offset+visible, len(l.Links))
+		sourceStyle := lipgloss.NewStyle().Foreground(theme.Text)
+
+		for i := offset; i < end; i++ {
+			link := l.Links[i]
+			label := link.Label
+			if label != link.URL {
+				label = fmt.Sprintf("%s  %s", label, link.URL)
+			}
+			row := padString(link.Source, sourceWidth) + "  " + truncateString(label, boxWidth-sourceWidth-2)
+			row = padString(row, boxWidth)
+
+			if i == l.Cursor {
+				row = theme.SelectedStyle().Render(row)
+			} else {
+				row = sourceStyle.Render(padString(link.Source, sourceWidth)) + row[sourceWidth:]
+			}
+			output.WriteString(row + "\n")
+		}
+
+		output.WriteString(sourceStyle.Render(fmt.Sprintf("[%d/%d]", l.Cursor+1, len(l.Links))))
+	}
+
+	output.WriteString("\n\n")
+	output.WriteString(hints)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Render(output.String())
+
+	return lipgloss.Place(l.Width, l.Height, lipgloss.Center, lipgloss.Center, box)
+}
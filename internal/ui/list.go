@@ -3,88 +3,92 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
 )
 
-type Repository struct {
-	Slug  string
-	Name  string
-	Links Links
-}
-
-type RepoList struct {
-	Repositories []Repository
+type PRList struct {
+	PullRequests []PR
 	Cursor       int
 	Width        int
 	Height       int
 	Focused      bool
-	SelectedIdx  int
-}
-
-func NewRepoList(width, height int) *RepoList {
-	return &RepoList{
-		Repositories: []Repository{},
-		Cursor:       0,
-		Width:        width,
-		Height:       height,
-		Focused:      false,
-		SelectedIdx:  -1,
-	}
+	Columns      []Column
+	Sort         SortOrder
+	// Offset is the index of the first visible row
+	Offset int
+	// Progress describes the fetch of a dashboard or view, shown next to the title
+	Progress string
+	// unsorted keeps the API order so clearing the sort restores it
+	unsorted []PR
 }
 
-func (r *RepoList) SetRepositories(repos []Repository) {
-	r.Repositories = repos
-	if r.Cursor >= len(repos) {
-		r.Cursor = 0
-	}
-}
-
-func (r *RepoList) MoveUp() {
-	if r.Cursor > 0 {
-		r.Cursor--
-	}
-}
-
-func (r *RepoList) MoveDown() {
-	if r.Cursor < len(r.Repositories)-1 {
-		r.Cursor++
-	}
+type PR struct {
+	ID           int
+	Title        string
+	Description  string
+	Author       string
+	State        string
+	Links        Links
+	CreatedOn    string
+	UpdatedOn    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Workspace    string
+	Repo         string
+	SourceBranch string
+	// SourceRepo is the full name of the repository of the source branch,
+	// another one than Workspace/Repo for PRs from forks
+	SourceRepo   string
+	DestBranch   string
+	Approvals    int
+	Reviewers    int
+	BuildStatus  string
+	CommentCount int
+	TaskCount    int
+	Relation     Relation
 }
 
-func (r *RepoList) GetSelected() *Repository {
-	if r.Cursor >= 0 && r.Cursor < len(r.Repositories) {
-		return &r.Repositories[r.Cursor]
-	}
-	return nil
-}
+// Relation is how the logged-in user is involved in a PR
+type Relation int
+
+const (
+	RelationNone Relation = iota
+	// RelationAuthored marks PRs the user created
+	RelationAuthored
+	// RelationReviewRequested marks PRs waiting for the user's review
+	RelationReviewRequested
+	// RelationApproved marks PRs the user already approved
+	RelationApproved
+)
 
-func (r *RepoList) SetSelected(idx int) {
-	if idx >= 0 && idx < len(r.Repositories) {
-		r.SelectedIdx = idx
+// relationMarkerWidth is the gutter reserved in front of each PR row
+const relationMarkerWidth = 2
+
+// relationMarker returns the icon shown in front of a PR row and its color
+func relationMarker(r Relation) (string, lipgloss.TerminalColor) {
+	switch r {
+	case RelationAuthored:
+		return "★", theme.Accent
+	case RelationReviewRequested:
+		return "●", theme.Declined
+	case RelationApproved:
+		return "✓", theme.Open
+	default:
+		return " ", theme.Text
 	}
 }
 
-type PRList struct {
-	PullRequests []PR
-	Cursor       int
-	Width        int
-	Height       int
-	Focused      bool
+// Key identifies a PR across repositories, IDs alone are only unique per repository
+func (pr PR) Key() string {
+	return fmt.Sprintf("%s/%s#%d", pr.Workspace, pr.Repo, pr.ID)
 }
 
-type PR struct {
-	ID          int
-	Title       string
-	Description string
-	Author      string
-	State       string
-	Links       Links
-	CreatedOn   string
-	UpdatedOn   string
-	Workspace   string
-	Repo        string
+// Version identifies a PR as of its last update, to tell when loaded data is stale
+func (pr PR) Version() string {
+	return pr.Key() + "@" + pr.UpdatedOn
 }
 
 type Links struct {
@@ -95,33 +99,156 @@ type HTML struct {
 	Href string
 }
 
-func NewPRList(width, height int) *PRList {
+func NewPRList(width, height int, columns []Column) *PRList {
 	return &PRList{
 		PullRequests: []PR{},
 		Cursor:       0,
 		Width:        width,
 		Height:       height,
 		Focused:      true, // List is focused by default
+		Columns:      columns,
 	}
 }
 
 func (p *PRList) SetPRs(prs []PR) {
-	p.PullRequests = prs
+	p.unsorted = prs
+	p.PullRequests = sortPRs(prs, p.Sort)
 	if p.Cursor >= len(prs) {
 		p.Cursor = 0
 	}
+	p.Offset = scrollOffset(p.Offset, p.Cursor, p.visibleRows())
+}
+
+// SetSort re-sorts the list, keeping the cursor on the same PR
+func (p *PRList) SetSort(order SortOrder) {
+	p.Sort = order
+
+	selectedKey := ""
+	if selected := p.GetSelected(); selected != nil {
+		selectedKey = selected.Key()
+	}
+
+	p.PullRequests = sortPRs(p.unsorted, order)
+	for i, pr := range p.PullRequests {
+		if pr.Key() == selectedKey {
+			p.Cursor = i
+			break
+		}
+	}
+	p.Offset = scrollOffset(p.Offset, p.Cursor, p.visibleRows())
+}
+
+// CycleSort advances the sort to the next visible column, wrapping around to
+// the unsorted API order after the last one
+func (p *PRList) CycleSort() {
+	next := SortOrder{}
+	for i, col := range p.Columns {
+		if p.Sort.Column == "" {
+			next = SortOrder{Column: col.ID}
+			break
+		}
+		if col.ID == p.Sort.Column {
+			if i+1 < len(p.Columns) {
+				next = SortOrder{Column: p.Columns[i+1].ID}
+			}
+			break
+		}
+	}
+	p.SetSort(next)
+}
+
+// ReverseSort flips the sort direction, sorting by the first column if unsorted
+func (p *PRList) ReverseSort() {
+	order := p.Sort
+	if order.Column == "" && len(p.Columns) > 0 {
+		order.Column = p.Columns[0].ID
+	}
+	order.Desc = !order.Desc
+	p.SetSort(order)
+}
+
+// SelectKey moves the cursor to the PR with the given Key, reporting whether it is listed
+func (p *PRList) SelectKey(key string) bool {
+	for i, pr := range p.PullRequests {
+		if pr.Key() == key {
+			p.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// SetRelations updates how the user relates to each PR, by its Key
+func (p *PRList) SetRelations(relations map[string]Relation) {
+	for _, prs := range [][]PR{p.unsorted, p.PullRequests} {
+		for i := range prs {
+			prs[i].Relation = relations[prs[i].Key()]
+		}
+	}
+}
+
+// SetBuildStatuses fills in the build status of each PR by its Key
+func (p *PRList) SetBuildStatuses(statuses map[string]string) {
+	for _, prs := range [][]PR{p.unsorted, p.PullRequests} {
+		for i := range prs {
+			if status, ok := statuses[prs[i].Key()]; ok {
+				prs[i].BuildStatus = status
+			}
+		}
+	}
+}
+
+// SetTaskCounts updates the number of open tasks of PRs by their Key
+func (p *PRList) SetTaskCounts(counts map[string]int) {
+	for _, prs := range [][]PR{p.unsorted, p.PullRequests} {
+		for i := range prs {
+			if count, ok := counts[prs[i].Key()]; ok {
+				prs[i].TaskCount = count
+			}
+		}
+	}
+}
+
+// HasColumn reports whether a column is shown
+func (p *PRList) HasColumn(id string) bool {
+	for _, col := range p.Columns {
+		if col.ID == id {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *PRList) MoveUp() {
 	if p.Cursor > 0 {
 		p.Cursor--
 	}
+	p.Offset = scrollOffset(p.Offset, p.Cursor, p.visibleRows())
 }
 
 func (p *PRList) MoveDown() {
 	if p.Cursor < len(p.PullRequests)-1 {
 		p.Cursor++
 	}
+	p.Offset = scrollOffset(p.Offset, p.Cursor, p.visibleRows())
+}
+
+// SetCursor moves the cursor to a row, e.g. one that was clicked
+func (p *PRList) SetCursor(idx int) {
+	if idx >= 0 && idx < len(p.PullRequests) {
+		p.Cursor = idx
+		p.Offset = scrollOffset(p.Offset, p.Cursor, p.visibleRows())
+	}
+}
+
+// RowAt returns the PR index at a line relative to the pane's top border,
+// or -1 when the line is not a PR row
+func (p *PRList) RowAt(y int) int {
+	return rowAt(y, p.Offset, p.visibleRows(), len(p.PullRequests))
+}
+
+func (p *PRList) visibleRows() int {
+	return max(p.Height-listChromeLines, 1)
 }
 
 func (p *PRList) GetSelected() *PR {
@@ -131,14 +258,44 @@ func (p *PRList) GetSelected() *PR {
 	return nil
 }
 
+// listChromeLines is the number of lines a list pane spends on its title,
+// header, separators and status line
+const listChromeLines = 6
+
+// listHeaderLines is the number of lines between a list's top border and its first row
+const listHeaderLines = 5
+
+// scrollOffset adjusts offset so that the cursor stays within the visible rows
+func scrollOffset(offset, cursor, visible int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+visible {
+		return cursor - visible + 1
+	}
+	return max(offset, 0)
+}
+
+func rowAt(y, offset, visible, total int) int {
+	row := y - listHeaderLines
+	if row < 0 || row >= visible {
+		return -1
+	}
+	if idx := offset + row; idx < total {
+		return idx
+	}
+	return -1
+}
+
 func truncateString(s string, width int) string {
 	if runewidth.StringWidth(s) <= width {
 		return s
 	}
-	for len(s) > 0 {
-		s = s[:len(s)-1]
-		if runewidth.StringWidth(s) <= width-2 {
-			return s + ".."
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if runewidth.StringWidth(string(runes)) <= width-2 {
+			return string(runes) + ".."
 		}
 	}
 	return ".."
@@ -159,100 +316,62 @@ func (p *PRList) View() string {
 			Height(p.Height).
 			Align(lipgloss.Center, lipgloss.Center).
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#565f89")).
+			BorderForeground(theme.Border).
 			Render("No pull requests found")
 	}
 
-	colPR := 5
-	colTitle := 40
-	colAuthor := 18
-	colState := 6
-	colRepo := 40
-
-	separatorWidth := 10 // " │ " between columns (3 chars * 4 separators - 2 for border)
-	totalFixedWidth := colPR + colTitle + colAuthor + colState + colRepo + separatorWidth
 	availableWidth := p.Width - 4 // -4 for padding and border
+	columns, widths := allocateWidths(p.Columns, p.PullRequests, p.Sort, availableWidth-relationMarkerWidth)
 
-	if availableWidth < totalFixedWidth {
-		scaleFactor := float64(availableWidth) / float64(totalFixedWidth)
-		colTitle = int(float64(colTitle) * scaleFactor)
-		colAuthor = int(float64(colAuthor) * scaleFactor)
-		colRepo = int(float64(colRepo) * scaleFactor)
-	}
-
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#ffffff")).
-		Background(lipgloss.Color("#1f2335"))
+	headerStyle := theme.HeaderStyle()
 
-	headerText := fmt.Sprintf("%s │ %s │ %s │ %s │ %s",
-		padString("PR#", colPR),
-		padString("Title", colTitle),
-		padString("Author", colAuthor),
-		padString("State", colState),
-		padString("Workspace/Repo", colRepo),
-	)
-	header := headerStyle.Render(headerText)
+	headerCells := make([]string, len(columns))
+	for i, col := range columns {
+		headerCells[i] = padString(headerLabel(col, p.Sort), widths[i])
+	}
+	header := headerStyle.Render(strings.Repeat(" ", relationMarkerWidth) + strings.Join(headerCells, columnSeparator))
 
 	var rows []string
-	maxRows := p.Height - 4 // Leave room for header, border, status
+	end := min(p.Offset+p.visibleRows(), len(p.PullRequests))
 
-	for i, pr := range p.PullRequests {
-		if i >= maxRows {
-			break
-		}
+	for i := p.Offset; i < end; i++ {
+		pr := p.PullRequests[i]
 
-		prNum := fmt.Sprintf("%d", pr.ID)
-		title := truncateString(pr.Title, colTitle-2)
-		author := truncateString(pr.Author, colAuthor-2)
-
-		var stateColor string
-		switch pr.State {
-		case "OPEN":
-			stateColor = "#7aa2f7"
-		case "MERGED":
-			stateColor = "#bb9af7"
-		case "DECLINED":
-			stateColor = "#f7768e"
-		default:
-			stateColor = "#a9b1d6"
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = padString(col.Value(pr), widths[j])
+			if col.ID == "state" && i != p.Cursor {
+				cells[j] = lipgloss.NewStyle().Foreground(theme.StateColor(pr.State)).Render(cells[j])
+			}
 		}
 
-		repo := fmt.Sprintf("%s/%s", pr.Workspace, pr.Repo)
-		repo = truncateString(repo, colRepo-2)
-
-		rowText := fmt.Sprintf("%s │ %s │ %s │ %s │ %s",
-			padString(prNum, colPR),
-			padString(title, colTitle),
-			padString(author, colAuthor),
-			padString(pr.State, colState),
-			padString(repo, colRepo),
-		)
+		icon, color := relationMarker(pr.Relation)
+		marker := padString(icon, relationMarkerWidth)
 
+		rowText := strings.Join(cells, columnSeparator)
 		if i == p.Cursor {
-			rowText = lipgloss.NewStyle().
-				Background(lipgloss.Color("33")).
-				Foreground(lipgloss.Color("255")).
-				Render(rowText)
+			rowText = theme.SelectedStyle().Render(marker + rowText)
 		} else {
-			stateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(stateColor))
-			stateStyled := stateStyle.Render(pr.State)
-			rowText = strings.Replace(rowText, pr.State, stateStyled, 1)
+			rowText = lipgloss.NewStyle().Foreground(color).Bold(true).Render(marker) + rowText
 		}
 
 		rows = append(rows, rowText)
 	}
 
 	separatorText := strings.Repeat("─", availableWidth)
-	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("#565f89")).Render(separatorText)
+	separator := lipgloss.NewStyle().Foreground(theme.Border).Render(separatorText)
 
 	var output strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aa2f7"))
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Accent)
 	if p.Focused {
 		titleStyle = titleStyle.Bold(true)
 	}
-	output.WriteString(titleStyle.Render("[1]-PRs") + "\n")
+	output.WriteString(titleStyle.Render("[1]-PRs"))
+	if p.Progress != "" {
+		output.WriteString(" " + lipgloss.NewStyle().Foreground(theme.Border).Render(p.Progress))
+	}
+	output.WriteString("\n")
 	output.WriteString(separator + "\n")
 
 	output.WriteString(header + "\n")
@@ -261,116 +380,14 @@ func (p *PRList) View() string {
 		output.WriteString(row + "\n")
 	}
 
-	statusText := fmt.Sprintf("[%d/%d] Use ↑↓ to navigate, Enter to open, r to refresh, q to quit",
-		p.Cursor+1, len(p.PullRequests))
+	statusText := fmt.Sprintf("[%d/%d]", p.Cursor+1, len(p.PullRequests))
 	output.WriteString("\n" + statusText)
 
-	borderColor := lipgloss.Color("#565f89")
-	if p.Focused {
-		borderColor = lipgloss.Color("#7aa2f7")
-	}
-
 	borderStyle := lipgloss.NewStyle().
 		Width(p.Width).
 		Height(p.Height).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
-		Padding(0, 1)
-
-	return borderStyle.Render(output.String())
-}
-
-func (r *RepoList) View() string {
-	if len(r.Repositories) == 0 {
-		return lipgloss.NewStyle().
-			Width(r.Width).
-			Height(r.Height).
-			Align(lipgloss.Center, lipgloss.Center).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#565f89")).
-			Render("No repositories found")
-	}
-
-	colName := r.Width - 4
-	availableWidth := r.Width - 4 // -4 for padding and border
-
-	if availableWidth < colName {
-		colName = availableWidth
-	}
-
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#ffffff")).
-		Background(lipgloss.Color("#1f2335"))
-
-	headerText := padString("Name", colName)
-	header := headerStyle.Render(headerText)
-
-	var rows []string
-	maxRows := r.Height - 4 // Leave room for header, border, status
-
-	for i, repo := range r.Repositories {
-		if i >= maxRows {
-			break
-		}
-
-		name := truncateString(repo.Name, colName-2)
-
-		rowText := padString(name, colName)
-
-		if i == r.Cursor && i == r.SelectedIdx {
-			rowText = lipgloss.NewStyle().
-				Background(lipgloss.Color("33")).
-				Foreground(lipgloss.Color("255")).
-				Bold(true).
-				Render(rowText)
-		} else if i == r.Cursor {
-			rowText = lipgloss.NewStyle().
-				Background(lipgloss.Color("33")).
-				Foreground(lipgloss.Color("255")).
-				Render(rowText)
-		} else if i == r.SelectedIdx {
-			rowText = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#7aa2f7")).
-				Bold(true).
-				Render(" " + rowText)
-		}
-
-		rows = append(rows, rowText)
-	}
-
-	separatorText := strings.Repeat("─", availableWidth)
-	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("#565f89")).Render(separatorText)
-
-	var output strings.Builder
-
-	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aa2f7"))
-	if r.Focused {
-		titleStyle = titleStyle.Bold(true)
-	}
-	output.WriteString(titleStyle.Render("[3]-Repos") + "\n")
-	output.WriteString(separator + "\n")
-
-	output.WriteString(header + "\n")
-	output.WriteString(separator + "\n")
-	for _, row := range rows {
-		output.WriteString(row + "\n")
-	}
-
-	statusText := fmt.Sprintf("[%d/%d] Use ↑↓ to navigate, Enter to see PRs",
-		r.Cursor+1, len(r.Repositories))
-	output.WriteString("\n" + statusText)
-
-	borderColor := lipgloss.Color("#565f89")
-	if r.Focused {
-		borderColor = lipgloss.Color("#7aa2f7")
-	}
-
-	borderStyle := lipgloss.NewStyle().
-		Width(r.Width).
-		Height(r.Height).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(borderColor).
+		BorderForeground(theme.BorderColor(p.Focused)).
 		Padding(0, 1)
 
 	return borderStyle.Render(output.String())
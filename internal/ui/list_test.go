@@ -0,0 +1,53 @@
+package ui
+
+import "testing"
+
+func TestCycleSort(t *testing.T) {
+	list := NewPRList(80, 20, mustColumns(t, "id", "title", "author"))
+	list.SetPRs([]PR{
+		{ID: 2, Title: "b", Author: "Zed", Workspace: "ws", Repo: "app"},
+		{ID: 1, Title: "c", Author: "Amy", Workspace: "ws", Repo: "app"},
+		{ID: 3, Title: "a", Author: "Max", Workspace: "ws", Repo: "app"},
+	})
+	list.Cursor = 1 // #1
+
+	steps := []struct {
+		want     SortOrder
+		wantIDs  []int
+		selected int
+	}{
+		{SortOrder{Column: "id"}, []int{1, 2, 3}, 1},
+		{SortOrder{Column: "title"}, []int{3, 2, 1}, 1},
+		{SortOrder{Column: "author"}, []int{1, 3, 2}, 1},
+		{SortOrder{}, []int{2, 1, 3}, 1},
+		{SortOrder{Column: "id"}, []int{1, 2, 3}, 1},
+	}
+
+	for i, step := range steps {
+		list.CycleSort()
+		if list.Sort != step.want {
+			t.Fatalf("step %d: sort = %+v, want %+v", i, list.Sort, step.want)
+		}
+		for j, id := range step.wantIDs {
+			if list.PullRequests[j].ID != id {
+				t.Errorf("step %d: row %d = #%d, want #%d", i, j, list.PullRequests[j].ID, id)
+			}
+		}
+		if selected := list.GetSelected(); selected == nil || selected.ID != step.selected {
+			t.Errorf("step %d: selected = %+v, want #%d", i, selected, step.selected)
+		}
+	}
+}
+
+func TestCycleSortResetsDirection(t *testing.T) {
+	list := NewPRList(80, 20, mustColumns(t, "id", "title"))
+	list.ReverseSort()
+	if want := (SortOrder{Column: "id", Desc: true}); list.Sort != want {
+		t.Fatalf("ReverseSort: sort = %+v, want %+v", list.Sort, want)
+	}
+
+	list.CycleSort()
+	if want := (SortOrder{Column: "title"}); list.Sort != want {
+		t.Errorf("CycleSort: sort = %+v, want %+v", list.Sort, want)
+	}
+}
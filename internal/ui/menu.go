@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MenuItem is an entry of a Menu, picked with the cursor or its shortcut key
+type MenuItem struct {
+	Key   string
+	Label string
+	Value string
+	// ID optionally identifies what the item stands for, such as a comment
+	ID int
+}
+
+// Menu is a small overlay listing choices, such as what to copy
+type Menu struct {
+	Title   string
+	Items   []MenuItem
+	Cursor  int
+	Width   int
+	Height  int
+	Visible bool
+	// Loading is set while the items are being fetched
+	Loading bool
+}
+
+func NewMenu(title string) *Menu {
+	return &Menu{Title: title}
+}
+
+func (m *Menu) Open(items []MenuItem) {
+	m.Items = items
+	m.Cursor = 0
+	m.Loading = false
+	m.Visible = true
+}
+
+func (m *Menu) Close() {
+	m.Visible = false
+}
+
+func (m *Menu) MoveUp() {
+	if m.Cursor > 0 {
+		m.Cursor--
+	}
+}
+
+func (m *Menu) MoveDown() {
+	if m.Cursor < len(m.Items)-1 {
+		m.Cursor++
+	}
+}
+
+func (m *Menu) GetSelected() *MenuItem {
+	if m.Cursor >= 0 && m.Cursor < len(m.Items) {
+		return &m.Items[m.Cursor]
+	}
+	return nil
+}
+
+// ItemForKey returns the item whose shortcut is the pressed key
+func (m *Menu) ItemForKey(key string) *MenuItem {
+	for i := range m.Items {
+		if m.Items[i].Key == key {
+			return &m.Items[i]
+		}
+	}
+	return nil
+}
+
+// View renders the menu centered in the terminal
+func (m *Menu) View(hints string) string {
+	boxWidth := max(min(m.Width-8, 80), 20)
+
+	labelWidth := 0
+	for _, item := range m.Items {
+		labelWidth = max(labelWidth, lipgloss.Width(item.Label))
+	}
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	output.WriteString(titleStyle.Render(m.Title))
+	output.WriteString("\n\n")
+
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Text)
+
+	visible := max(m.Height-10, 1)
+	offset := scrollOffset(0, m.Cursor, visible)
+	end := min(offset+visible, len(m.Items))
+
+	switch {
+	case m.Loading:
+		output.WriteString(valueStyle.Render("Loading...") + "\n")
+	case len(m.Items) == 0:
+		output.WriteString(valueStyle.Render("Nothing to show") + "\n")
+	}
+
+	for i := offset; i < end; i++ {
+		item := m.Items[i]
+		value := truncateString(strings.ReplaceAll(item.Value, "\n", " "), max(boxWidth-labelWidth-5, 1))
+		if i == m.Cursor {
+			row := padString(item.Key+"  "+padString(item.Label, labelWidth)+"  "+value, boxWidth)
+			output.WriteString(theme.SelectedStyle().Render(row) + "\n")
+			continue
+		}
+		row := keyStyle.Render(item.Key) + "  " + padString(item.Label, labelWidth) + "  " + valueStyle.Render(value)
+		output.WriteString(row + "\n")
+	}
+
+	output.WriteString("\n")
+	output.WriteString(hints)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Render(output.String())
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
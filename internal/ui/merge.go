@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CheckStatus is the outcome of a merge check
+type CheckStatus int
+
+const (
+	CheckPassed CheckStatus = iota
+	CheckFailed
+	CheckUnknown
+	// CheckPending waits for builds still running, it does not block a merge
+	CheckPending
+)
+
+func (s CheckStatus) icon() string {
+	switch s {
+	case CheckPassed:
+		return "✓"
+	case CheckFailed:
+		return "✗"
+	case CheckPending:
+		return "●"
+	default:
+		return "?"
+	}
+}
+
+func (s CheckStatus) color() lipgloss.TerminalColor {
+	switch s {
+	case CheckPassed:
+		return theme.Open
+	case CheckFailed:
+		return theme.Declined
+	default:
+		return theme.Text
+	}
+}
+
+// MergeCheck is one condition for merging a PR, as listed on the checks tab
+type MergeCheck struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// FailedChecks counts the checks that block a merge
+func FailedChecks(checks []MergeCheck) int {
+	failed := 0
+	for _, c := range checks {
+		if c.Status == CheckFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// renderChecklist lists the checks with their outcome, wrapped to width
+func renderChecklist(checks []MergeCheck, width int) []string {
+	nameWidth := 0
+	for _, c := range checks {
+		nameWidth = max(nameWidth, lipgloss.Width(c.Name))
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	indent := strings.Repeat(" ", nameWidth+4)
+
+	var lines []string
+	for _, c := range checks {
+		icon := lipgloss.NewStyle().Foreground(c.Status.color()).Render(c.Status.icon())
+		detail := ansi.Wrap(c.Detail, max(width-len(indent), 1), "")
+		for i, part := range strings.Split(detail, "\n") {
+			prefix := indent
+			if i == 0 {
+				prefix = icon + " " + padString(c.Name, nameWidth) + "  "
+			}
+			lines = append(lines, prefix+textStyle.Render(part))
+		}
+	}
+	return lines
+}
+
+// checksVerdict summarizes whether the checks allow a merge
+func checksVerdict(checks []MergeCheck) string {
+	failed := FailedChecks(checks)
+	unknown, pending := 0, 0
+	for _, c := range checks {
+		switch c.Status {
+		case CheckUnknown:
+			unknown++
+		case CheckPending:
+			pending++
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return lipgloss.NewStyle().Foreground(theme.Declined).Render(fmt.Sprintf("Not ready to merge: %d of %d checks failed", failed, len(checks)))
+	case pending > 0:
+		return lipgloss.NewStyle().Foreground(theme.Text).Render("Ready to merge, builds are still running")
+	case unknown > 0:
+		return lipgloss.NewStyle().Foreground(theme.Open).Render(fmt.Sprintf("Ready to merge, %d checks could not be verified", unknown))
+	default:
+		return lipgloss.NewStyle().Foreground(theme.Open).Render("Ready to merge")
+	}
+}
+
+// renderChecks builds the checks tab
+func (p *PRDetail) renderChecks() string {
+	checks, ok := p.checks[p.PR.Version()]
+	switch {
+	case p.PR.State != "OPEN":
+		return "Only open PRs can be merged"
+	case !ok:
+		return "Checking merge readiness..."
+	case checks.err != nil:
+		return ansi.Wrap("Failed to check merge readiness: "+checks.err.Error(), p.contentWidth(), "")
+	}
+
+	lines := []string{checksVerdict(checks.values), ""}
+	lines = append(lines, renderChecklist(checks.values, p.contentWidth())...)
+	return strings.Join(lines, "\n")
+}
+
+// MergeDialog confirms merging a PR, showing its merge checks. A merge is
+// refused while checks fail unless overriding is allowed
+type MergeDialog struct {
+	PR            *PR
+	Width         int
+	Height        int
+	Visible       bool
+	Loading       bool
+	AllowOverride bool
+	// Merging is set once the merge has been requested
+	Merging bool
+
+	checks []MergeCheck
+	err    error
+}
+
+func NewMergeDialog() *MergeDialog {
+	return &MergeDialog{}
+}
+
+// Open shows the dialog for a PR while its checks are being evaluated
+func (d *MergeDialog) Open(pr PR) {
+	d.PR = &pr
+	d.checks = nil
+	d.err = nil
+	d.Loading = true
+	d.Merging = false
+	d.Visible = true
+}
+
+func (d *MergeDialog) SetChecks(checks []MergeCheck, err error) {
+	d.checks = checks
+	d.err = err
+	d.Loading = false
+}
+
+func (d *MergeDialog) Close() {
+	d.Visible = false
+}
+
+// CanMerge reports whether the checks allow merging without an override
+func (d *MergeDialog) CanMerge() bool {
+	return !d.Loading && !d.Merging && d.err == nil && FailedChecks(d.checks) == 0
+}
+
+// CanOverride reports whether the PR may be merged despite failed checks
+func (d *MergeDialog) CanOverride() bool {
+	return d.AllowOverride && !d.Loading && !d.Merging && !d.CanMerge()
+}
+
+// View renders the dialog centered in the terminal
+func (d *MergeDialog) View(hints string) string {
+	boxWidth := max(min(d.Width-8, 80), 20)
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	title := fmt.Sprintf("Merge #%d %s", d.PR.ID, d.PR.Title)
+	output.WriteString(titleStyle.Render(ansi.Truncate(title, boxWidth, "…")))
+	output.WriteString("\n")
+	output.WriteString(textStyle.Render(ansi.Truncate(d.PR.SourceBranch+" → "+d.PR.DestBranch, boxWidth, "…")))
+	output.WriteString("\n\n")
+
+	switch {
+	case d.Loading:
+		output.WriteString(textStyle.Render("Checking merge readiness..."))
+	case d.err != nil:
+		output.WriteString(ansi.Wrap("Failed to check merge readiness: "+d.err.Error(), boxWidth, ""))
+	default:
+		output.WriteString(checksVerdict(d.checks))
+		output.WriteString("\n\n")
+		output.WriteString(strings.Join(renderChecklist(d.checks, boxWidth), "\n"))
+	}
+	output.WriteString("\n\n")
+
+	var notice string
+	switch {
+	case d.Merging:
+		notice = "Merging..."
+	case d.Loading, d.CanMerge():
+	case d.AllowOverride:
+		notice = "Merging is disabled until the checks pass, override to merge anyway"
+	default:
+		notice = "Merging is disabled until the checks pass"
+	}
+	if notice != "" {
+		output.WriteString(textStyle.Render(ansi.Wrap(notice, boxWidth, "")))
+		output.WriteString("\n\n")
+	}
+	output.WriteString(hints)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Render(output.String())
+
+	return lipgloss.Place(d.Width, d.Height, lipgloss.Center, lipgloss.Center, box)
+}
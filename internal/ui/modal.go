@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// URLModal shows a URL that could not be opened so it can be copied instead
+type URLModal struct {
+	URL     string
+	Reason  string
+	Width   int
+	Height  int
+	Visible bool
+}
+
+func NewURLModal() *URLModal {
+	return &URLModal{}
+}
+
+func (u *URLModal) Open(url string, err error) {
+	u.URL = url
+	u.Reason = ""
+	if err != nil {
+		u.Reason = err.Error()
+	}
+	u.Visible = true
+}
+
+func (u *URLModal) Close() {
+	u.Visible = false
+}
+
+// View renders the modal centered in the terminal, wrapping the URL in full
+func (u *URLModal) View(hints string) string {
+	boxWidth := max(min(u.Width-8, 100), 20)
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	output.WriteString(titleStyle.Render("Could not open browser"))
+	output.WriteString("\n\n")
+
+	if u.Reason != "" {
+		reasonStyle := lipgloss.NewStyle().Foreground(theme.Text)
+		output.WriteString(reasonStyle.Render(ansi.Wrap(u.Reason, boxWidth, " ")))
+		output.WriteString("\n\n")
+	}
+
+	// Hard wrap so the URL can be selected from the terminal as is
+	for i, line := range strings.Split(ansi.Hardwrap(u.URL, boxWidth, false), "\n") {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString(Hyperlink(u.URL, line))
+	}
+	output.WriteString("\n\n")
+	output.WriteString(hints)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Render(output.String())
+
+	return lipgloss.Place(u.Width, u.Height, lipgloss.Center, lipgloss.Center, box)
+}
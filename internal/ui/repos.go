@@ -0,0 +1,518 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+type Repository struct {
+	Slug       string
+	Name       string
+	Links      Links
+	ProjectKey string
+	Project    string
+	Language   string
+	Private    bool
+	UpdatedAt  time.Time
+	// OpenPRs is the number of open pull requests, -1 until it has been counted
+	OpenPRs int
+}
+
+// SidebarView is an entry listed above the repositories that loads PRs from
+// somewhere other than a single repository, such as a dashboard
+type SidebarView struct {
+	ID   string
+	Name string
+}
+
+// RepoSort is the order of the repository list
+type RepoSort int
+
+const (
+	RepoSortName RepoSort = iota
+	// RepoSortActivity lists the most recently updated repositories first
+	RepoSortActivity
+	// RepoSortProject groups repositories by project
+	RepoSortProject
+)
+
+func (s RepoSort) String() string {
+	switch s {
+	case RepoSortActivity:
+		return "activity"
+	case RepoSortProject:
+		return "project"
+	default:
+		return "name"
+	}
+}
+
+// RepoList lists the sidebar views followed by the repositories. Cursor and
+// SelectedIdx index into that combined list
+type RepoList struct {
+	Views []SidebarView
+	// Repositories are the repositories shown, after the project filter and sort
+	Repositories []Repository
+	Cursor       int
+	Width        int
+	Height       int
+	Focused      bool
+	SelectedIdx  int
+	// Offset is the index of the first visible row
+	Offset int
+	// Role is the role scope the repositories were fetched with, shown in the title
+	Role string
+	// Project is the key of the project to filter by, empty for all projects
+	Project string
+	Sort    RepoSort
+
+	all          []Repository
+	selectedSlug string
+	pinned       map[string]bool
+	recent       []string
+	recentShown  map[string]bool
+}
+
+// maxRecentShown caps the recently opened repositories listed below the pinned ones
+const maxRecentShown = 5
+
+func NewRepoList(width, height int) *RepoList {
+	return &RepoList{
+		Repositories: []Repository{},
+		Cursor:       0,
+		Width:        width,
+		Height:       height,
+		Focused:      false,
+		SelectedIdx:  -1,
+	}
+}
+
+func (r *RepoList) SetRepositories(repos []Repository) {
+	r.all = repos
+	r.apply()
+}
+
+// apply rebuilds the shown repositories from the project filter and sort,
+// keeping the cursor and the selection on the same repositories
+func (r *RepoList) apply() {
+	cursorSlug := ""
+	if selected := r.GetSelected(); selected != nil {
+		cursorSlug = selected.Slug
+	}
+
+	shown := make([]Repository, 0, len(r.all))
+	for _, repo := range r.all {
+		if r.Project == "" || repo.ProjectKey == r.Project {
+			shown = append(shown, repo)
+		}
+	}
+
+	sort.SliceStable(shown, func(i, j int) bool {
+		a, b := shown[i], shown[j]
+		switch r.Sort {
+		case RepoSortActivity:
+			return a.UpdatedAt.After(b.UpdatedAt)
+		case RepoSortProject:
+			if a.Project != b.Project {
+				return strings.ToLower(a.Project) < strings.ToLower(b.Project)
+			}
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	r.Repositories = r.group(shown)
+
+	if r.SelectedIdx >= len(r.Views) {
+		r.SelectedIdx = -1
+		if idx := r.indexOf(r.selectedSlug); idx >= 0 {
+			r.SelectedIdx = idx
+		}
+	}
+
+	if idx := r.indexOf(cursorSlug); idx >= 0 {
+		r.Cursor = idx
+	} else if r.Cursor >= r.Len() {
+		r.Cursor = 0
+	}
+	r.Offset = scrollOffset(r.Offset, r.Cursor, r.visibleRows())
+}
+
+// group moves pinned repositories to the top, followed by the recently
+// opened ones in the order they were opened, then everything else
+func (r *RepoList) group(repos []Repository) []Repository {
+	bySlug := make(map[string]Repository, len(repos))
+	for _, repo := range repos {
+		bySlug[repo.Slug] = repo
+	}
+
+	grouped := make([]Repository, 0, len(repos))
+	for _, repo := range repos {
+		if r.pinned[repo.Slug] {
+			grouped = append(grouped, repo)
+		}
+	}
+
+	r.recentShown = make(map[string]bool)
+	for _, slug := range r.recent {
+		repo, ok := bySlug[slug]
+		if !ok || r.pinned[slug] {
+			continue
+		}
+		if len(r.recentShown) == maxRecentShown {
+			break
+		}
+		r.recentShown[slug] = true
+		grouped = append(grouped, repo)
+	}
+
+	for _, repo := range repos {
+		if !r.pinned[repo.Slug] && !r.recentShown[repo.Slug] {
+			grouped = append(grouped, repo)
+		}
+	}
+	return grouped
+}
+
+// SetPinned sets the slugs of the favorite repositories
+func (r *RepoList) SetPinned(slugs []string) {
+	r.pinned = make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		r.pinned[slug] = true
+	}
+	r.apply()
+}
+
+// SetRecent sets the slugs of the recently opened repositories, most recent first
+func (r *RepoList) SetRecent(slugs []string) {
+	r.recent = slugs
+	r.apply()
+}
+
+// marker returns the prefix shown before a repository name
+func (r *RepoList) marker(slug string) string {
+	switch {
+	case r.pinned[slug]:
+		return "★ "
+	case r.recentShown[slug]:
+		return "↻ "
+	default:
+		return ""
+	}
+}
+
+// indexOf returns the row of the repository with the given slug, or -1
+func (r *RepoList) indexOf(slug string) int {
+	if slug == "" {
+		return -1
+	}
+	for i, repo := range r.Repositories {
+		if repo.Slug == slug {
+			return len(r.Views) + i
+		}
+	}
+	return -1
+}
+
+// Len is the number of rows, views and repositories combined
+func (r *RepoList) Len() int {
+	return len(r.Views) + len(r.Repositories)
+}
+
+// Projects lists the keys of the projects the repositories belong to
+func (r *RepoList) Projects() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, repo := range r.all {
+		if repo.ProjectKey != "" && !seen[repo.ProjectKey] {
+			seen[repo.ProjectKey] = true
+			keys = append(keys, repo.ProjectKey)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetProject filters the repositories to a project key, empty for all projects
+func (r *RepoList) SetProject(key string) {
+	r.Project = key
+	r.apply()
+}
+
+// CycleProject filters by the next project, going back to all projects after the last
+func (r *RepoList) CycleProject() {
+	projects := r.Projects()
+	next := ""
+	for i, key := range projects {
+		if key == r.Project {
+			if i+1 < len(projects) {
+				next = projects[i+1]
+			}
+			r.SetProject(next)
+			return
+		}
+	}
+	if r.Project == "" && len(projects) > 0 {
+		next = projects[0]
+	}
+	r.SetProject(next)
+}
+
+// CycleSort switches between sorting by name, activity and project
+func (r *RepoList) CycleSort() {
+	r.Sort = (r.Sort + 1) % (RepoSortProject + 1)
+	r.apply()
+}
+
+// SetOpenPRCounts fills in the open pull request count of each repository by slug
+func (r *RepoList) SetOpenPRCounts(counts map[string]int) {
+	for _, repos := range [][]Repository{r.all, r.Repositories} {
+		for i := range repos {
+			if count, ok := counts[repos[i].Slug]; ok {
+				repos[i].OpenPRs = count
+			}
+		}
+	}
+}
+
+func (r *RepoList) MoveUp() {
+	if r.Cursor > 0 {
+		r.Cursor--
+	}
+	r.Offset = scrollOffset(r.Offset, r.Cursor, r.visibleRows())
+}
+
+func (r *RepoList) MoveDown() {
+	if r.Cursor < r.Len()-1 {
+		r.Cursor++
+	}
+	r.Offset = scrollOffset(r.Offset, r.Cursor, r.visibleRows())
+}
+
+// SetCursor moves the cursor to a row, e.g. one that was clicked
+func (r *RepoList) SetCursor(idx int) {
+	if idx >= 0 && idx < r.Len() {
+		r.Cursor = idx
+		r.Offset = scrollOffset(r.Offset, r.Cursor, r.visibleRows())
+	}
+}
+
+// RowAt returns the row index at a line relative to the pane's top border,
+// or -1 when the line is not a row
+func (r *RepoList) RowAt(y int) int {
+	return rowAt(y, r.Offset, r.visibleRows(), r.Len())
+}
+
+func (r *RepoList) visibleRows() int {
+	return max(r.Height-listChromeLines, 1)
+}
+
+// GetSelected returns the repository under the cursor, nil when it is on a view
+func (r *RepoList) GetSelected() *Repository {
+	idx := r.Cursor - len(r.Views)
+	if idx >= 0 && idx < len(r.Repositories) {
+		return &r.Repositories[idx]
+	}
+	return nil
+}
+
+// GetSelectedView returns the view under the cursor, nil when it is on a repository
+func (r *RepoList) GetSelectedView() *SidebarView {
+	if r.Cursor >= 0 && r.Cursor < len(r.Views) {
+		return &r.Views[r.Cursor]
+	}
+	return nil
+}
+
+func (r *RepoList) SetSelected(idx int) {
+	if idx < 0 || idx >= r.Len() {
+		return
+	}
+	r.SelectedIdx = idx
+	r.selectedSlug = ""
+	if idx >= len(r.Views) {
+		r.selectedSlug = r.Repositories[idx-len(r.Views)].Slug
+	}
+}
+
+// SelectRepo marks the repository with the given slug as the loaded one and
+// moves the cursor to it, reporting whether it is listed
+func (r *RepoList) SelectRepo(slug string) bool {
+	if idx := r.indexOf(slug); idx >= 0 {
+		r.SetSelected(idx)
+		r.SetCursor(idx)
+		return true
+	}
+	return false
+}
+
+// SelectView marks the view with the given ID as the loaded one and moves the
+// cursor to it, reporting whether it exists
+func (r *RepoList) SelectView(id string) bool {
+	for i, view := range r.Views {
+		if view.ID == id {
+			r.SetSelected(i)
+			r.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// repoColumn is an optional column of the repository pane
+type repoColumn struct {
+	title string
+	width int
+	value func(repo Repository) string
+}
+
+// repoColumns are listed in display order. When the pane is narrow they are
+// dropped from the right so the name keeps at least minRepoNameWidth
+var repoColumns = []repoColumn{
+	{"Project", 8, func(repo Repository) string { return repo.ProjectKey }},
+	{"Lang", 10, func(repo Repository) string { return repo.Language }},
+	{"Vis", 7, func(repo Repository) string {
+		if repo.Private {
+			return "private"
+		}
+		return "public"
+	}},
+	{"Updated", 8, func(repo Repository) string { return relativeTime(repo.UpdatedAt, time.Now()) }},
+	{"PRs", 3, func(repo Repository) string {
+		if repo.OpenPRs < 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d", repo.OpenPRs)
+	}},
+}
+
+const minRepoNameWidth = 16
+
+// fitRepoColumns returns the optional columns that fit next to the name and the name width
+func fitRepoColumns(available int) ([]repoColumn, int) {
+	sepWidth := runewidth.StringWidth(columnSeparator)
+	columns := repoColumns
+	for len(columns) > 0 {
+		used := 0
+		for _, col := range columns {
+			used += col.width + sepWidth
+		}
+		if available-used >= minRepoNameWidth {
+			return columns, available - used
+		}
+		columns = columns[:len(columns)-1]
+	}
+	return nil, available
+}
+
+// title describes the role scope, project filter and sort next to the pane name
+func (r *RepoList) title() string {
+	var parts []string
+	if r.Role != "" {
+		parts = append(parts, r.Role)
+	}
+	if r.Project != "" {
+		parts = append(parts, "project "+r.Project)
+	}
+	if r.Sort != RepoSortName {
+		parts = append(parts, "by "+r.Sort.String())
+	}
+	if len(parts) == 0 {
+		return "[3]-Repos"
+	}
+	return "[3]-Repos (" + strings.Join(parts, " · ") + ")"
+}
+
+func (r *RepoList) View() string {
+	if r.Len() == 0 {
+		return lipgloss.NewStyle().
+			Width(r.Width).
+			Height(r.Height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Border).
+			Render("No repositories found")
+	}
+
+	availableWidth := r.Width - 4 // -4 for padding and border
+	columns, colName := fitRepoColumns(availableWidth)
+
+	headerStyle := theme.HeaderStyle()
+
+	headerCells := []string{padString("Name", colName)}
+	for _, col := range columns {
+		headerCells = append(headerCells, padString(col.title, col.width))
+	}
+	header := headerStyle.Render(strings.Join(headerCells, columnSeparator))
+
+	var rows []string
+	end := min(r.Offset+r.visibleRows(), r.Len())
+
+	for i := r.Offset; i < end; i++ {
+		var cells []string
+		if i < len(r.Views) {
+			cells = []string{padString("◆ "+r.Views[i].Name, availableWidth)}
+		} else {
+			repo := r.Repositories[i-len(r.Views)]
+			cells = []string{padString(truncateString(r.marker(repo.Slug)+repo.Name, colName-2), colName)}
+			for _, col := range columns {
+				cells = append(cells, padString(col.value(repo), col.width))
+			}
+		}
+
+		rowText := strings.Join(cells, columnSeparator)
+
+		if i == len(r.Views)-1 && i != r.Cursor && i != r.SelectedIdx {
+			// Underline the last view to set the views apart from the repositories
+			rowText = lipgloss.NewStyle().Underline(true).Render(rowText)
+		}
+
+		if i == r.Cursor && i == r.SelectedIdx {
+			rowText = theme.SelectedStyle().
+				Bold(true).
+				Render(rowText)
+		} else if i == r.Cursor {
+			rowText = theme.SelectedStyle().Render(rowText)
+		} else if i == r.SelectedIdx {
+			rowText = lipgloss.NewStyle().
+				Foreground(theme.Accent).
+				Bold(true).
+				Render(" " + rowText)
+		}
+
+		rows = append(rows, rowText)
+	}
+
+	separatorText := strings.Repeat("─", availableWidth)
+	separator := lipgloss.NewStyle().Foreground(theme.Border).Render(separatorText)
+
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+	if r.Focused {
+		titleStyle = titleStyle.Bold(true)
+	}
+	output.WriteString(titleStyle.Render(truncateString(r.title(), availableWidth)) + "\n")
+	output.WriteString(separator + "\n")
+
+	output.WriteString(header + "\n")
+	output.WriteString(separator + "\n")
+	for _, row := range rows {
+		output.WriteString(row + "\n")
+	}
+
+	statusText := fmt.Sprintf("[%d/%d]", r.Cursor+1, r.Len())
+	output.WriteString("\n" + statusText)
+
+	borderStyle := lipgloss.NewStyle().
+		Width(r.Width).
+		Height(r.Height).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.BorderColor(r.Focused)).
+		Padding(0, 1)
+
+	return borderStyle.Render(output.String())
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusBar renders the footer line: key hints on the left and the
+// logged-in identity on the right
+type StatusBar struct {
+	Width     int
+	User      string
+	Nickname  string
+	Workspace string
+	// Message briefly replaces the hints, e.g. to confirm a copy
+	Message string
+}
+
+// Identity renders the right-hand side of the footer, empty until the user is known
+func (s *StatusBar) Identity() string {
+	if s.User == "" {
+		return ""
+	}
+
+	name := s.User
+	if s.Nickname != "" && s.Nickname != s.User {
+		name += " (@" + s.Nickname + ")"
+	}
+
+	user := lipgloss.NewStyle().Foreground(theme.Accent).Render("● " + name)
+	workspace := lipgloss.NewStyle().Foreground(theme.Text).Render(" · " + s.Workspace)
+	return " " + user + workspace
+}
+
+// View joins the hints with the identity, right-aligning the latter
+func (s *StatusBar) View(hints string) string {
+	identity := s.Identity()
+	if s.Message != "" {
+		message := truncateString(s.Message, max(s.Width-lipgloss.Width(identity)-1, 1))
+		hints = lipgloss.NewStyle().Foreground(theme.Accent).Render(" " + message)
+	}
+	gap := s.Width - lipgloss.Width(hints) - lipgloss.Width(identity)
+	if gap < 0 {
+		return hints
+	}
+	return hints + strings.Repeat(" ", gap) + identity
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Task is a checklist item of a PR, as listed on the tasks tab
+type Task struct {
+	ID        int
+	Resolved  bool
+	Content   string
+	Creator   string
+	CreatedAt time.Time
+	// CommentID is the comment the task is attached to, 0 for free-form tasks
+	CommentID int
+}
+
+// TaskDraft is a task typed on the tasks tab, ready to be created
+type TaskDraft struct {
+	Content   string
+	CommentID int
+}
+
+// OpenTasks counts the unresolved tasks
+func OpenTasks(tasks []Task) int {
+	open := 0
+	for _, t := range tasks {
+		if !t.Resolved {
+			open++
+		}
+	}
+	return open
+}
+
+// StartTask focuses the new task prompt, prefilled with text when the task
+// is created from a comment
+func (p *PRDetail) StartTask(text string, commentID int) tea.Cmd {
+	p.Composing = true
+	p.taskComment = commentID
+	p.taskInput.Width = max(p.contentWidth()-3, 1)
+	p.taskInput.SetValue(text)
+	p.taskInput.CursorEnd()
+	return p.taskInput.Focus()
+}
+
+// UpdateTaskInput handles key presses while a new task is typed. Enter
+// returns the draft to create, Esc discards it
+func (p *PRDetail) UpdateTaskInput(msg tea.KeyMsg) (tea.Cmd, *TaskDraft) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		content := strings.TrimSpace(p.taskInput.Value())
+		p.stopComposing()
+		if content == "" {
+			return nil, nil
+		}
+		return nil, &TaskDraft{Content: content, CommentID: p.taskComment}
+	case tea.KeyEsc:
+		p.stopComposing()
+		return nil, nil
+	}
+
+	var cmd tea.Cmd
+	p.taskInput, cmd = p.taskInput.Update(msg)
+	return cmd, nil
+}
+
+func (p *PRDetail) stopComposing() {
+	p.Composing = false
+	p.taskComment = 0
+	p.taskInput.Blur()
+	p.taskInput.Reset()
+}
+
+// renderTasks builds the tasks tab, open tasks first
+func (p *PRDetail) renderTasks() string {
+	tasks, ok := p.tasks[p.PR.Version()]
+	switch {
+	case !ok:
+		return "Loading tasks..."
+	case tasks.err != nil:
+		return ansi.Wrap("Failed to load tasks: "+tasks.err.Error(), p.contentWidth(), "")
+	case len(tasks.values) == 0:
+		p.rowsTop = 0
+		return "No tasks"
+	}
+
+	now := time.Now()
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	doneStyle := lipgloss.NewStyle().Foreground(theme.Open)
+
+	open := OpenTasks(tasks.values)
+	lines := []string{
+		textStyle.Render(fmt.Sprintf("%d open, %d resolved", open, len(tasks.values)-open)),
+		"",
+	}
+	p.rowsTop = len(lines)
+
+	for i, t := range tasks.values {
+		check := "[ ] "
+		if t.Resolved {
+			check = "[✓] "
+		}
+
+		meta := " " + t.Creator + " · " + relativeTime(t.CreatedAt, now)
+		if t.CommentID != 0 {
+			meta = " ↳ comment ·" + meta
+		}
+		contentWidth := max(p.contentWidth()-4-len([]rune(meta)), 1)
+		content := padString(strings.ReplaceAll(t.Content, "\n", " "), contentWidth)
+
+		switch {
+		case i == p.cursors[TabTasks]:
+			lines = append(lines, theme.SelectedStyle().Render(check+content+meta))
+		case t.Resolved:
+			lines = append(lines, doneStyle.Render(check)+textStyle.Render(content+meta))
+		default:
+			lines = append(lines, check+content+textStyle.Render(meta))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"path"
+	"strings"
+)
+
+// Template is a PR description template
+type Template struct {
+	Name string
+	Body string
+}
+
+// TemplateVars are the values substituted for the placeholders of a template
+type TemplateVars struct {
+	Branch      string
+	Destination string
+	// Commits holds the summaries of the commits to merge, oldest first
+	Commits []string
+}
+
+// ExpandTemplate fills in the placeholders of a template: {{branch}},
+// {{destination}}, {{jira}} for the issue key in the branch name and
+// {{commits}} for a list of the commit summaries
+func ExpandTemplate(body string, vars TemplateVars) string {
+	commits := make([]string, len(vars.Commits))
+	for i, c := range vars.Commits {
+		commits[i] = "- " + c
+	}
+
+	return strings.NewReplacer(
+		"{{branch}}", vars.Branch,
+		"{{destination}}", vars.Destination,
+		"{{jira}}", JiraKey(vars.Branch),
+		"{{commits}}", strings.Join(commits, "\n"),
+	).Replace(body)
+}
+
+// DefaultTitle suggests a PR title: the summary of a single commit, or else
+// the branch name in words, e.g. "ABC-12 fix login" for feature/ABC-12-fix-login
+func DefaultTitle(vars TemplateVars) string {
+	if len(vars.Commits) == 1 {
+		return vars.Commits[0]
+	}
+	if vars.Branch == "" {
+		return ""
+	}
+
+	name := path.Base(vars.Branch)
+	key := JiraKey(name)
+	rest := strings.TrimLeft(strings.Replace(name, key, "", 1), "-_ ")
+	words := strings.Join(strings.FieldsFunc(rest, func(r rune) bool { return r == '-' || r == '_' }), " ")
+	return strings.TrimSpace(key + " " + words)
+}
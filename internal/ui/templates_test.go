@@ -0,0 +1,65 @@
+package ui
+
+import "testing"
+
+func TestExpandTemplate(t *testing.T) {
+	vars := TemplateVars{
+		Branch:      "feature/ABC-12-fix-login",
+		Destination: "main",
+		Commits:     []string{"Fix the form", "Add a test"},
+	}
+
+	tests := []struct {
+		name string
+		body string
+		vars TemplateVars
+		want string
+	}{
+		{"no placeholders", "## Summary", vars, "## Summary"},
+		{"branch", "From {{branch}}", vars, "From feature/ABC-12-fix-login"},
+		{"destination", "Into {{destination}}", vars, "Into main"},
+		{"jira", "## {{jira}}", vars, "## ABC-12"},
+		{"jira without a key", "[{{jira}}]", TemplateVars{Branch: "fix-login"}, "[]"},
+		{"commits", "{{commits}}", vars, "- Fix the form\n- Add a test"},
+		{"no commits", "Changes:\n{{commits}}", TemplateVars{}, "Changes:\n"},
+		{"repeated placeholders", "{{jira}} {{jira}}", vars, "ABC-12 ABC-12"},
+		{"unknown placeholder", "{{author}}", vars, "{{author}}"},
+		{
+			"all together",
+			"{{jira}}: {{branch}} → {{destination}}\n\n{{commits}}",
+			vars,
+			"ABC-12: feature/ABC-12-fix-login → main\n\n- Fix the form\n- Add a test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandTemplate(tt.body, tt.vars); got != tt.want {
+				t.Errorf("ExpandTemplate(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		vars TemplateVars
+		want string
+	}{
+		{"no branch", TemplateVars{}, ""},
+		{"single commit", TemplateVars{Branch: "feature/ABC-12-x", Commits: []string{"Fix the form"}}, "Fix the form"},
+		{"branch with a Jira key", TemplateVars{Branch: "feature/ABC-12-fix-login", Commits: []string{"a", "b"}}, "ABC-12 fix login"},
+		{"key in the middle", TemplateVars{Branch: "fix-ABC-12-login"}, "ABC-12 fix login"},
+		{"branch without a key", TemplateVars{Branch: "bugfix/fix_login-form"}, "fix login form"},
+		{"only a key", TemplateVars{Branch: "ABC-12"}, "ABC-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultTitle(tt.vars); got != tt.want {
+				t.Errorf("DefaultTitle(%+v) = %q, want %q", tt.vars, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the color palette shared by every component
+type Theme struct {
+	Name       string
+	Accent     lipgloss.TerminalColor // titles, focused borders, links
+	Border     lipgloss.TerminalColor // unfocused borders and separators
+	Text       lipgloss.TerminalColor // secondary text
+	HeaderFg   lipgloss.TerminalColor
+	HeaderBg   lipgloss.TerminalColor
+	SelectedFg lipgloss.TerminalColor
+	SelectedBg lipgloss.TerminalColor
+	Open       lipgloss.TerminalColor
+	Merged     lipgloss.TerminalColor
+	Declined   lipgloss.TerminalColor
+	// Glamour is the glamour standard style used for markdown
+	Glamour string
+	// NoColor marks the selection with reverse video instead of a background color
+	NoColor bool
+}
+
+// ThemeSpec describes a user-defined theme as read from the config file
+type ThemeSpec struct {
+	Base    string
+	Glamour string
+	Colors  map[string]string
+}
+
+// theme is the active palette, replaced with SetTheme at startup
+var theme = TokyoNightTheme()
+
+func SetTheme(t Theme) {
+	theme = t
+}
+
+func ActiveTheme() Theme {
+	return theme
+}
+
+func TokyoNightTheme() Theme {
+	return Theme{
+		Name:       "tokyonight",
+		Accent:     lipgloss.Color("#7aa2f7"),
+		Border:     lipgloss.Color("#565f89"),
+		Text:       lipgloss.Color("#a9b1d6"),
+		HeaderFg:   lipgloss.Color("#ffffff"),
+		HeaderBg:   lipgloss.Color("#1f2335"),
+		SelectedFg: lipgloss.Color("255"),
+		SelectedBg: lipgloss.Color("33"),
+		Open:       lipgloss.Color("#7aa2f7"),
+		Merged:     lipgloss.Color("#bb9af7"),
+		Declined:   lipgloss.Color("#f7768e"),
+		Glamour:    "dark",
+	}
+}
+
+func LightTheme() Theme {
+	return Theme{
+		Name:       "light",
+		Accent:     lipgloss.Color("#2e7de9"),
+		Border:     lipgloss.Color("#a8aecb"),
+		Text:       lipgloss.Color("#3760bf"),
+		HeaderFg:   lipgloss.Color("#3760bf"),
+		HeaderBg:   lipgloss.Color("#d0d5e3"),
+		SelectedFg: lipgloss.Color("#ffffff"),
+		SelectedBg: lipgloss.Color("#2e7de9"),
+		Open:       lipgloss.Color("#2e7de9"),
+		Merged:     lipgloss.Color("#9854f1"),
+		Declined:   lipgloss.Color("#f52a65"),
+		Glamour:    "light",
+	}
+}
+
+func HighContrastTheme() Theme {
+	return Theme{
+		Name:       "high-contrast",
+		Accent:     lipgloss.Color("14"),
+		Border:     lipgloss.Color("15"),
+		Text:       lipgloss.Color("15"),
+		HeaderFg:   lipgloss.Color("0"),
+		HeaderBg:   lipgloss.Color("15"),
+		SelectedFg: lipgloss.Color("0"),
+		SelectedBg: lipgloss.Color("11"),
+		Open:       lipgloss.Color("10"),
+		Merged:     lipgloss.Color("13"),
+		Declined:   lipgloss.Color("9"),
+		Glamour:    "dark",
+	}
+}
+
+func NoColorTheme() Theme {
+	return Theme{
+		Name:       "no-color",
+		Accent:     lipgloss.NoColor{},
+		Border:     lipgloss.NoColor{},
+		Text:       lipgloss.NoColor{},
+		HeaderFg:   lipgloss.NoColor{},
+		HeaderBg:   lipgloss.NoColor{},
+		SelectedFg: lipgloss.NoColor{},
+		SelectedBg: lipgloss.NoColor{},
+		Open:       lipgloss.NoColor{},
+		Merged:     lipgloss.NoColor{},
+		Declined:   lipgloss.NoColor{},
+		Glamour:    "notty",
+		NoColor:    true,
+	}
+}
+
+var bundledThemes = map[string]func() Theme{
+	"tokyonight":    TokyoNightTheme,
+	"light":         LightTheme,
+	"high-contrast": HighContrastTheme,
+	"no-color":      NoColorTheme,
+}
+
+// ResolveTheme picks the theme to use. NO_COLOR always wins, an empty name or
+// "auto" follows the terminal background, and user themes shadow bundled ones
+func ResolveTheme(name string, custom map[string]ThemeSpec) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColorTheme(), nil
+	}
+
+	if name == "" || name == "auto" {
+		if lipgloss.HasDarkBackground() {
+			return TokyoNightTheme(), nil
+		}
+		return LightTheme(), nil
+	}
+
+	if spec, ok := custom[name]; ok {
+		return buildTheme(name, spec)
+	}
+
+	if bundled, ok := bundledThemes[name]; ok {
+		return bundled(), nil
+	}
+
+	return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames(custom), ", "))
+}
+
+// buildTheme applies a user-defined theme on top of its bundled base
+func buildTheme(name string, spec ThemeSpec) (Theme, error) {
+	baseName := spec.Base
+	if baseName == "" {
+		baseName = "tokyonight"
+	}
+
+	base, ok := bundledThemes[baseName]
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %q: unknown base theme %q", name, baseName)
+	}
+
+	t := base()
+	t.Name = name
+	if spec.Glamour != "" {
+		t.Glamour = spec.Glamour
+	}
+
+	slots := map[string]*lipgloss.TerminalColor{
+		"accent":      &t.Accent,
+		"border":      &t.Border,
+		"text":        &t.Text,
+		"header_fg":   &t.HeaderFg,
+		"header_bg":   &t.HeaderBg,
+		"selected_fg": &t.SelectedFg,
+		"selected_bg": &t.SelectedBg,
+		"open":        &t.Open,
+		"merged":      &t.Merged,
+		"declined":    &t.Declined,
+	}
+
+	for slot, value := range spec.Colors {
+		target, ok := slots[slot]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %q: unknown color %q", name, slot)
+		}
+		*target = lipgloss.Color(value)
+	}
+
+	return t, nil
+}
+
+func themeNames(custom map[string]ThemeSpec) []string {
+	var names []string
+	for name := range bundledThemes {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := bundledThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StateColor returns the color used for a PR state
+func (t Theme) StateColor(state string) lipgloss.TerminalColor {
+	switch state {
+	case "OPEN":
+		return t.Open
+	case "MERGED":
+		return t.Merged
+	case "DECLINED":
+		return t.Declined
+	default:
+		return t.Text
+	}
+}
+
+// SelectedStyle highlights the row under the cursor
+func (t Theme) SelectedStyle() lipgloss.Style {
+	if t.NoColor {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().
+		Background(t.SelectedBg).
+		Foreground(t.SelectedFg)
+}
+
+// HeaderStyle is used for table headers
+func (t Theme) HeaderStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.HeaderFg).
+		Background(t.HeaderBg)
+}
+
+// BorderColor returns the pane border color depending on focus
+func (t Theme) BorderColor(focused bool) lipgloss.TerminalColor {
+	if focused {
+		return t.Accent
+	}
+	return t.Border
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ActivityKind is the type of a timeline event
+type ActivityKind string
+
+const (
+	ActivityComment        ActivityKind = "comment"
+	ActivityApproval       ActivityKind = "approval"
+	ActivityChangesRequest ActivityKind = "changes_request"
+	ActivityUpdate         ActivityKind = "update"
+)
+
+// ActivityKinds lists the event types in the order they are offered for collapsing
+var ActivityKinds = []ActivityKind{ActivityComment, ActivityApproval, ActivityChangesRequest, ActivityUpdate}
+
+// Plural names a number of events of the kind
+func (k ActivityKind) Plural() string {
+	switch k {
+	case ActivityComment:
+		return "comments"
+	case ActivityApproval:
+		return "approvals"
+	case ActivityChangesRequest:
+		return "change requests"
+	case ActivityUpdate:
+		return "updates"
+	default:
+		return string(k)
+	}
+}
+
+func (k ActivityKind) icon() string {
+	switch k {
+	case ActivityComment:
+		return lipgloss.NewStyle().Foreground(theme.Accent).Render("✎")
+	case ActivityApproval:
+		return lipgloss.NewStyle().Foreground(theme.Open).Render("✓")
+	case ActivityChangesRequest:
+		return lipgloss.NewStyle().Foreground(theme.Declined).Render("✗")
+	default:
+		return lipgloss.NewStyle().Foreground(theme.Text).Render("↻")
+	}
+}
+
+// ActivityEvent is something that happened to a PR
+type ActivityEvent struct {
+	Kind    ActivityKind
+	Actor   string
+	At      time.Time
+	Summary string
+}
+
+// timelineTimeWidth fits the longest relative time, e.g. "11mo ago"
+const timelineTimeWidth = 9
+
+// renderTimeline lists events oldest first with relative times. Runs of
+// collapsed kinds are folded into a single line, and events after lastSeen
+// are marked as new
+func renderTimeline(events []ActivityEvent, collapsed map[ActivityKind]bool, lastSeen, now time.Time) string {
+	if len(events) == 0 {
+		return "No activity yet"
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	newStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	actorStyle := lipgloss.NewStyle().Bold(true)
+
+	isNew := func(e ActivityEvent) bool {
+		return !lastSeen.IsZero() && e.At.After(lastSeen)
+	}
+
+	var b strings.Builder
+
+	if !lastSeen.IsZero() {
+		fresh := 0
+		for _, e := range events {
+			if isNew(e) {
+				fresh++
+			}
+		}
+		b.WriteString(textStyle.Render(fmt.Sprintf("Last visit %s, %d new", relativeTime(lastSeen, now), fresh)))
+		b.WriteString("\n\n")
+	}
+
+	for i := 0; i < len(events); i++ {
+		e := events[i]
+
+		if collapsed[e.Kind] {
+			run, fresh := 0, 0
+			for ; i+run < len(events) && events[i+run].Kind == e.Kind; run++ {
+				if isNew(events[i+run]) {
+					fresh++
+				}
+			}
+			i += run - 1
+
+			line := fmt.Sprintf("▸ %d %s", run, e.Kind.Plural())
+			if run == 1 {
+				line = "▸ 1 " + strings.TrimSuffix(e.Kind.Plural(), "s")
+			}
+			if fresh > 0 {
+				line += fmt.Sprintf(" (%d new)", fresh)
+			}
+			b.WriteString(strings.Repeat(" ", timelineTimeWidth+2) + textStyle.Render(line) + "\n")
+			continue
+		}
+
+		marker := "  "
+		if isNew(e) {
+			marker = newStyle.Render("● ")
+		}
+
+		b.WriteString(textStyle.Render(padString(relativeTime(e.At, now), timelineTimeWidth)))
+		b.WriteString(marker)
+		b.WriteString(e.Kind.icon() + " " + actorStyle.Render(e.Actor) + " " + e.Summary + "\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
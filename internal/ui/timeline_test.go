@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderTimelineCollapse(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return time.Date(2026, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	events := []ActivityEvent{
+		{Kind: ActivityUpdate, Actor: "Alice", At: at(10), Summary: "opened the pull request"},
+		{Kind: ActivityComment, Actor: "Bob", At: at(11), Summary: "commented: one"},
+		{Kind: ActivityComment, Actor: "Bob", At: at(12), Summary: "commented: two"},
+		{Kind: ActivityApproval, Actor: "Bob", At: at(13), Summary: "approved"},
+		{Kind: ActivityComment, Actor: "Carol", At: at(14), Summary: "commented: three"},
+	}
+
+	tests := []struct {
+		name      string
+		collapsed []ActivityKind
+		lastSeen  time.Time
+		want      []string
+	}{
+		{
+			"nothing collapsed",
+			nil, time.Time{},
+			[]string{"opened the pull request", "commented: one", "commented: two", "approved", "commented: three"},
+		},
+		{
+			"runs of a kind fold into one line each",
+			[]ActivityKind{ActivityComment}, time.Time{},
+			[]string{"opened the pull request", "▸ 2 comments", "approved", "▸ 1 comment"},
+		},
+		{
+			"several kinds",
+			[]ActivityKind{ActivityUpdate, ActivityApproval}, time.Time{},
+			[]string{"▸ 1 update", "commented: one", "commented: two", "▸ 1 approval", "commented: three"},
+		},
+		{
+			"folded lines count the new events",
+			[]ActivityKind{ActivityComment}, at(11),
+			[]string{"Last visit 13h ago, 3 new", "opened the pull request", "▸ 2 comments (1 new)", "approved", "▸ 1 comment (1 new)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collapsed := make(map[ActivityKind]bool)
+			for _, kind := range tt.collapsed {
+				collapsed[kind] = true
+			}
+
+			var got []string
+			for _, line := range strings.Split(ansi.Strip(renderTimeline(events, collapsed, tt.lastSeen, now)), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					got = append(got, line)
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("renderTimeline() =\n%s\nwant lines ending with %q", strings.Join(got, "\n"), tt.want)
+			}
+			for i, line := range got {
+				if !strings.HasSuffix(line, tt.want[i]) {
+					t.Errorf("line %d = %q, want it to end with %q", i, line, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if got := renderTimeline(nil, nil, time.Time{}, now); got != "No activity yet" {
+		t.Errorf("renderTimeline(nil) = %q", got)
+	}
+}
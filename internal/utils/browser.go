@@ -1,29 +1,109 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 )
 
-// OpenBrowser opens a URL in the default browser
-func OpenBrowser(url string) error {
-	var cmd *exec.Cmd
+// urlPlaceholder marks where the URL goes in an open command template
+const urlPlaceholder = "{url}"
+
+// Opener opens URLs in a browser
+type Opener struct {
+	// Command is a template such as "firefox --new-tab {url}"; the URL is
+	// appended when it has no placeholder. Empty falls back to $BROWSER and
+	// then the platform default
+	Command string
+}
+
+// Open tries each candidate command in turn until one starts
+func (o Opener) Open(url string) error {
+	candidates, err := o.commands(url)
+	if err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+
+	var errs []error
+	for _, args := range candidates {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			errs = append(errs, fmt.Errorf("%s not found", args[0]))
+			continue
+		}
+
+		cmd := exec.Command(args[0], args[1:]...)
+		if err := cmd.Start(); err != nil {
+			errs = append(errs, fmt.Errorf("%s failed: %w", args[0], err))
+			continue
+		}
+		// Reap the process so it does not linger as a zombie
+		go func() { _ = cmd.Wait() }()
+		return nil
+	}
+
+	return fmt.Errorf("failed to open browser: %w", errors.Join(errs...))
+}
+
+// commands lists the commands to try, most specific first
+func (o Opener) commands(url string) ([][]string, error) {
+	if o.Command != "" {
+		args := expandCommand(o.Command, url)
+		if len(args) == 0 {
+			return nil, errors.New("open_command is blank")
+		}
+		return [][]string{args}, nil
+	}
+
+	var candidates [][]string
+
+	// $BROWSER is a colon separated list, where %s stands for the URL
+	for _, browser := range strings.Split(os.Getenv("BROWSER"), ":") {
+		if args := expandCommand(strings.ReplaceAll(browser, "%s", urlPlaceholder), url); len(args) > 0 {
+			candidates = append(candidates, args)
+		}
+	}
 
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("open", url)
-	case "linux":
-		cmd = exec.Command("xdg-open", url)
+		candidates = append(candidates, []string{"open", url})
 	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-	default:
-		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+		candidates = append(candidates, []string{"rundll32", "url.dll,FileProtocolHandler", url})
+	case "linux", "freebsd", "openbsd", "netbsd":
+		// wslview hands the URL to the Windows browser
+		if IsWSL() {
+			candidates = append(candidates, []string{"wslview", url})
+		}
+		// xdg-open cannot do anything useful without a display
+		if os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"xdg-open", url})
+		}
 	}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to open browser: %w", err)
+	if len(candidates) == 0 {
+		return nil, errors.New("no browser available; set $BROWSER or open_command")
+	}
+	return candidates, nil
+}
+
+// expandCommand splits a command template into arguments, substituting the URL
+func expandCommand(template, url string) []string {
+	args := strings.Fields(template)
+	if len(args) == 0 {
+		return nil
 	}
 
-	return nil
+	substituted := false
+	for i, arg := range args {
+		if strings.Contains(arg, urlPlaceholder) {
+			args[i] = strings.ReplaceAll(arg, urlPlaceholder, url)
+			substituted = true
+		}
+	}
+	if !substituted {
+		args = append(args, url)
+	}
+	return args
 }
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Copy puts text on the clipboard. It emits an OSC 52 sequence, which
+// supporting terminals apply even over SSH, and also hands the text to the
+// first available clipboard tool for terminals that ignore the sequence
+func Copy(text string) error {
+	oscErr := copyOSC52(text)
+
+	toolErr := copyTool(text)
+	if toolErr == nil || oscErr == nil {
+		return nil
+	}
+
+	return fmt.Errorf("failed to copy to clipboard: %w", errors.Join(oscErr, toolErr))
+}
+
+// copyOSC52 writes the OSC 52 sequence straight to the terminal, wrapped for
+// tmux and screen so they pass it through
+func copyOSC52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open terminal: %w", err)
+	}
+	defer tty.Close()
+
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	if _, err := seq.WriteTo(tty); err != nil {
+		return fmt.Errorf("failed to write OSC 52 sequence: %w", err)
+	}
+	return nil
+}
+
+// clipboardTools lists the clipboard commands to try for the current platform
+func clipboardTools() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+
+	var tools [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		tools = append(tools, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	if IsWSL() {
+		tools = append(tools, []string{"clip.exe"})
+	}
+	return tools
+}
+
+func copyTool(text string) error {
+	for _, tool := range clipboardTools() {
+		if _, err := exec.LookPath(tool[0]); err != nil {
+			continue
+		}
+
+		cmd := exec.Command(tool[0], tool[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", tool[0], err)
+		}
+		return nil
+	}
+
+	return errors.New("no clipboard tool found (install xclip, wl-copy or pbcopy)")
+}
+
+// IsWSL reports whether lazy-bb runs under the Windows Subsystem for Linux
+func IsWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
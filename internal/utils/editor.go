@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// scissors separates the text being written from the context shown below
+// it, which is dropped when the draft is read back
+const scissors = "# ------------------------ >8 ------------------------"
+
+// ErrEmptyDraft is returned when the editor was closed without any text
+var ErrEmptyDraft = errors.New("nothing was written")
+
+// Draft is a temporary file edited in the user's editor
+type Draft struct {
+	Path string
+}
+
+// NewDraft writes text to a temporary file, followed by context lines
+// commented out below a scissors line. ext sets the file extension, which
+// editors use to pick a syntax
+func NewDraft(text, context, ext string) (*Draft, error) {
+	file, err := os.CreateTemp("", "lazy-bb-*"+ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create draft: %w", err)
+	}
+	defer file.Close()
+
+	var content strings.Builder
+	content.WriteString(text)
+	if !strings.HasSuffix(text, "\n") {
+		content.WriteString("\n")
+	}
+	content.WriteString("\n" + scissors + "\n")
+	content.WriteString("# Do not modify or remove the line above.\n")
+	content.WriteString("# Everything below it is ignored; save an empty text to abort.\n")
+	if context != "" {
+		content.WriteString("#\n")
+		for _, line := range strings.Split(strings.TrimRight(context, "\n"), "\n") {
+			content.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+		}
+	}
+
+	if _, err := file.WriteString(content.String()); err != nil {
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to write draft: %w", err)
+	}
+
+	return &Draft{Path: file.Name()}, nil
+}
+
+// Command returns the editor process for the draft: $VISUAL, then $EDITOR,
+// then vi (notepad on Windows). The variables may hold arguments, such as
+// "code --wait"; blank ones are skipped
+func (d *Draft) Command() *exec.Cmd {
+	args := strings.Fields(os.Getenv("VISUAL"))
+	if len(args) == 0 {
+		args = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(args) == 0 {
+		args = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			args = []string{"notepad"}
+		}
+	}
+
+	return exec.Command(args[0], append(args[1:], d.Path)...)
+}
+
+// Read returns the edited text without the context and surrounding blank
+// lines, or ErrEmptyDraft when there is none. The file is removed
+func (d *Draft) Read() (string, error) {
+	defer os.Remove(d.Path)
+
+	data, err := os.ReadFile(d.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read draft: %w", err)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if i := strings.Index(text, scissors); i >= 0 {
+		text = text[:i]
+	}
+	// Keep the indentation of the first line, which may be markdown
+	text = strings.TrimRight(strings.TrimLeft(text, "\n"), " \t\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrEmptyDraft
+	}
+
+	return text, nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SupportsHyperlinks guesses from the environment whether the terminal
+// renders OSC 8 hyperlinks. Unknown terminals are assumed not to
+func SupportsHyperlinks() bool {
+	// Multiplexers pass the sequences through inconsistently
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return false
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "rio":
+		return true
+	}
+
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+
+	// VTE based terminals (GNOME Terminal, Tilix, ...) support them since 0.50
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+
+	term := os.Getenv("TERM")
+	for _, name := range []string{"kitty", "alacritty", "foot", "ghostty", "wezterm"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+
+	return false
+}